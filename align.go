@@ -0,0 +1,204 @@
+package rpgtextbox
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	wordwrap "github.com/arran4/golang-wordwrap"
+	"golang.org/x/image/math/fixed"
+)
+
+// HorizontalTextAlignment defines where each line is placed across the text rect.
+type HorizontalTextAlignment int
+
+const (
+	// LeftAlignText starts each line at the left of the text rect, the default.
+	LeftAlignText HorizontalTextAlignment = iota
+	// CenterAlignText centers each line.
+	CenterAlignText
+	// RightAlignText ends each line at the right of the text rect.
+	RightAlignText
+	// JustifyText spreads the words of each line across the text rect, except the last line of a paragraph which is
+	// left aligned.
+	JustifyText
+)
+
+// apply implements the Option interface.
+func (a HorizontalTextAlignment) apply(box *TextBox) {
+	box.horizontalAlignment = a
+}
+
+// VerticalTextAlignment defines where the lines of a page are placed within the text rect.
+type VerticalTextAlignment int
+
+const (
+	// TopAlignText starts the page at the top of the text rect, the default.
+	TopAlignText VerticalTextAlignment = iota
+	// MiddleAlignText centers the page vertically.
+	MiddleAlignText
+	// BottomAlignText ends the page at the bottom of the text rect.
+	BottomAlignText
+)
+
+// apply implements the Option interface.
+func (a VerticalTextAlignment) apply(box *TextBox) {
+	box.verticalAlignment = a
+}
+
+// verticalOffset is how far below the top of a text rect height pixels tall text content pixels tall starts. Text
+// taller than the text rect is bottom aligned so the latest lines are shown.
+func (tb *TextBox) verticalOffset(content, height int) int {
+	if content > height {
+		return height - content
+	}
+	switch tb.verticalAlignment {
+	case MiddleAlignText:
+		return (height - content) / 2
+	case BottomAlignText:
+		return height - content
+	}
+	return 0
+}
+
+// renderLines draws the lines aligned within r of i. more is true if there is text after the lines, so the last line
+// is justified like any other.
+func (tb *TextBox) renderLines(i wordwrap.Image, ls []wordwrap.Line, r image.Rectangle, more bool, opts ...wordwrap.DrawOption) error {
+	if tb.horizontalAlignment == LeftAlignText && tb.verticalAlignment == TopAlignText {
+		return tb.wrapper.RenderLines(i, ls, r.Min, opts...)
+	}
+	height := 0
+	for _, l := range ls {
+		height += l.Size().Dy()
+	}
+	y := r.Min.Y + tb.verticalOffset(height, r.Dy())
+	for n, l := range ls {
+		s := l.Size()
+		width := lineWidth(l)
+		lr := image.Rect(r.Min.X, y, r.Min.X+s.Dx(), y+s.Dy())
+		y += s.Dy()
+		switch tb.horizontalAlignment {
+		case CenterAlignText:
+			lr = lr.Add(image.Pt((r.Dx()-width)/2, 0))
+		case RightAlignText:
+			lr = lr.Add(image.Pt(r.Dx()-width, 0))
+		case JustifyText:
+			if (n < len(ls)-1 || more) && !endsParagraph(l) {
+				if err := justifyLine(i, l, lr, r.Dx()-width, opts...); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := l.DrawLine(i.SubImage(lr).(wordwrap.Image), opts...); err != nil {
+			return fmt.Errorf("drawing line %d: %s", n, err)
+		}
+	}
+	return nil
+}
+
+// visibleBox returns true if the box takes up space at the end of a line, a whitespace page break box holding the
+// TextEndChevron counts.
+func visibleBox(b wordwrap.Box) bool {
+	if pb, ok := b.(*wordwrap.PageBreakBox); ok && pb.VisualBox != nil {
+		return true
+	}
+	return !b.Whitespace()
+}
+
+// lineWidth is the width of the line without trailing whitespace, rounded the same way DrawLine places boxes
+func lineWidth(l wordwrap.Line) int {
+	var width, end fixed.Int26_6
+	for _, b := range l.Boxes() {
+		width += b.AdvanceRect()
+		if visibleBox(b) {
+			end = width
+		}
+	}
+	return end.Round()
+}
+
+// endsParagraph returns true if the line ends with a line break from the text rather than one from wrapping
+func endsParagraph(l wordwrap.Line) bool {
+	bs := l.Boxes()
+	if len(bs) == 0 {
+		return true
+	}
+	lb, ok := bs[len(bs)-1].(*wordwrap.LineBreakBox)
+	return ok && lb.Box != nil && strings.ContainsAny(lb.TextValue(), "\r\n")
+}
+
+// justifyLine draws l into lr with extra pixels shared between the gaps between its words. The line is drawn once for
+// each distinct offset with the boxes at other offsets hidden, so the positions given to BoxDrawMaps and BoxRecorders
+// are where the boxes end up.
+func justifyLine(i wordwrap.Image, l wordwrap.Line, lr image.Rectangle, extra int, opts ...wordwrap.DrawOption) error {
+	bs := l.Boxes()
+	first, last := -1, -1
+	for n, b := range bs {
+		if visibleBox(b) {
+			if first < 0 {
+				first = n
+			}
+			last = n
+		}
+	}
+	var gaps []int
+	for n := first + 1; n < last; n++ {
+		if !visibleBox(bs[n]) {
+			gaps = append(gaps, n)
+		}
+	}
+	if len(gaps) == 0 || extra <= 0 {
+		return l.DrawLine(i.SubImage(lr).(wordwrap.Image), opts...)
+	}
+	// offsets[n] is how far box n moves right, each gap is widened by its share of extra
+	offsets := make([]int, len(bs))
+	for g, n := range gaps {
+		for m := n + 1; m < len(bs); m++ {
+			offsets[m] += extra*(g+1)/len(gaps) - extra*g/len(gaps)
+		}
+	}
+	config := wordwrap.NewDrawConfig(opts...)
+	var others []wordwrap.DrawOption
+	for _, opt := range opts {
+		switch opt.(type) {
+		case wordwrap.BoxDrawMap, wordwrap.BoxRecorder:
+		default:
+			others = append(others, opt)
+		}
+	}
+	for n, offset := range offsets {
+		if n > 0 && offset == offsets[n-1] {
+			continue
+		}
+		boxDrawMap := wordwrap.BoxDrawMap(func(box wordwrap.Box, dc *wordwrap.DrawConfig, bps *wordwrap.BoxPositionStats) wordwrap.Box {
+			if offsets[bps.NumberInLine] != offset {
+				// Returning nil would stop the following boxes advancing
+				return &hiddenBox{Box: box}
+			}
+			if config.BoxDrawMap != nil {
+				return config.BoxDrawMap(box, dc, bps)
+			}
+			return box
+		})
+		boxRecorder := wordwrap.BoxRecorder(func(box wordwrap.Box, min, max image.Point, bps *wordwrap.BoxPositionStats) {
+			if _, ok := box.(*hiddenBox); ok || config.BoxRecorder == nil {
+				return
+			}
+			config.BoxRecorder(box, min, max, bps)
+		})
+		drawOpts := append(append([]wordwrap.DrawOption{}, others...), boxDrawMap, boxRecorder)
+		if err := l.DrawLine(i.SubImage(lr.Add(image.Pt(offset, 0))).(wordwrap.Image), drawOpts...); err != nil {
+			return fmt.Errorf("drawing justified line: %s", err)
+		}
+	}
+	return nil
+}
+
+// hiddenBox takes up the space of the box it wraps without drawing anything
+type hiddenBox struct {
+	wordwrap.Box
+}
+
+// DrawBox draws nothing
+func (hb *hiddenBox) DrawBox(i wordwrap.Image, y fixed.Int26_6, dc *wordwrap.DrawConfig) {}
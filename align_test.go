@@ -0,0 +1,201 @@
+package rpgtextbox
+
+import (
+	"bytes"
+	"image"
+	"sort"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	wordwrap "github.com/arran4/golang-wordwrap"
+)
+
+// alignedLines draws the first page and returns the rects of the visible boxes on each line, top to bottom
+func alignedLines(t *testing.T, tb *TextBox, size image.Point) (image.Rectangle, [][]image.Rectangle) {
+	t.Helper()
+	sr := &shapeRecorder{}
+	tb.SetSpaceMap(sr)
+	bounds := image.Rectangle{Max: size}
+	if _, err := tb.DrawNextPageFrame(image.NewRGBA(bounds)); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	l, err := tb.newLayout(bounds)
+	if err != nil {
+		t.Fatalf("Error creating layout: %v", err)
+	}
+	lines := map[int][]image.Rectangle{}
+	for _, s := range *sr {
+		if bs, ok := s.(*BoxShape); ok && visibleBox(bs.Box) {
+			lines[bs.Rect.Min.Y] = append(lines[bs.Rect.Min.Y], bs.Rect)
+		}
+	}
+	var ys []int
+	for y := range lines {
+		ys = append(ys, y)
+	}
+	sort.Ints(ys)
+	var result [][]image.Rectangle
+	for _, y := range ys {
+		rs := lines[y]
+		sort.Slice(rs, func(i, j int) bool { return rs[i].Min.X < rs[j].Min.X })
+		result = append(result, rs)
+	}
+	return l.TextRect(), result
+}
+
+func TestTextAlignment(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(400, 200)
+	text := "The quick brown fox jumps over the lazy dog and keeps on running until the text wraps."
+	tests := []struct {
+		name       string
+		horizontal HorizontalTextAlignment
+		vertical   VerticalTextAlignment
+	}{
+		{"TopLeft", LeftAlignText, TopAlignText},
+		{"Center", CenterAlignText, MiddleAlignText},
+		{"BottomRight", RightAlignText, BottomAlignText},
+		{"Justify", JustifyText, TopAlignText},
+	}
+	for _, tt := range tests {
+		tb, err := NewSimpleTextBox(theme, text, size, tt.horizontal, tt.vertical)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", tt.name, err)
+		}
+		tr, lines := alignedLines(t, tb, size)
+		if len(lines) < 2 {
+			t.Fatalf("%s: expected the text to wrap got %d lines", tt.name, len(lines))
+		}
+		for n, rs := range lines {
+			left := rs[0].Min.X - tr.Min.X
+			right := tr.Max.X - rs[len(rs)-1].Max.X
+			if left < 0 || right < 0 {
+				t.Errorf("%s: line %d %v outside of %v", tt.name, n, rs, tr)
+			}
+			switch tt.horizontal {
+			case LeftAlignText:
+				if left != 0 {
+					t.Errorf("%s: line %d starts %d pixels in", tt.name, n, left)
+				}
+			case CenterAlignText:
+				if left-right > 1 || right-left > 1 {
+					t.Errorf("%s: line %d has %d pixels left and %d right", tt.name, n, left, right)
+				}
+			case RightAlignText:
+				if right > 1 {
+					t.Errorf("%s: line %d ends %d pixels in", tt.name, n, right)
+				}
+			case JustifyText:
+				last := n == len(lines)-1
+				if left != 0 || (right > 1) != last {
+					t.Errorf("%s: line %d has %d pixels left and %d right", tt.name, n, left, right)
+				}
+			}
+		}
+		top := lines[0][0].Min.Y - tr.Min.Y
+		bottom := tr.Max.Y - lines[len(lines)-1][0].Max.Y
+		switch tt.vertical {
+		case TopAlignText:
+			if top != 0 {
+				t.Errorf("%s: text starts %d pixels down", tt.name, top)
+			}
+		case MiddleAlignText:
+			if top-bottom > 1 || bottom-top > 1 {
+				t.Errorf("%s: text has %d pixels above and %d below", tt.name, top, bottom)
+			}
+		case BottomAlignText:
+			if bottom != 0 {
+				t.Errorf("%s: text ends %d pixels up", tt.name, bottom)
+			}
+		}
+	}
+}
+
+func TestTextAlignmentChevron(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(400, 150)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box. " +
+		"It goes on and on for a while longer so that there is more than one page."
+	tb, err := NewSimpleTextBox(theme, text, size, TextEndChevron, RightAlignText)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	sr := &shapeRecorder{}
+	tb.SetSpaceMap(sr)
+	bounds := image.Rectangle{Max: size}
+	if _, err := tb.DrawNextPageFrame(image.NewRGBA(bounds)); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	l, err := tb.newLayout(bounds)
+	if err != nil {
+		t.Fatalf("Error creating layout: %v", err)
+	}
+	found := false
+	for _, s := range *sr {
+		if bs, ok := s.(*BoxShape); ok {
+			if pb, ok := bs.Box.(*wordwrap.PageBreakBox); ok && pb.VisualBox != nil {
+				found = true
+				if d := l.TextRect().Max.X - bs.Rect.Max.X; d < 0 || d > 1 {
+					t.Errorf("Expected the chevron %v at the right of %v", bs.Rect, l.TextRect())
+				}
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected a chevron at the end of the text")
+	}
+}
+
+func TestTextAlignmentAnimations(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(400, 200)
+	text := "The quick brown fox jumps over the lazy dog and keeps on running until the text wraps."
+	static, err := NewSimpleTextBox(theme, text, size, JustifyText, BottomAlignText)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	want := image.NewRGBA(image.Rectangle{Max: size})
+	if _, err := static.DrawNextPageFrame(want); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	tests := []struct {
+		name      string
+		animation AnimationMode
+	}{
+		{"BoxByBox", NewBoxByBoxAnimation()},
+		{"LetterByLetter", NewLetterByLetterAnimation()},
+		{"Scroll", NewScrollAnimation()},
+	}
+	for _, tt := range tests {
+		tb, err := NewSimpleTextBox(theme, text, size, JustifyText, BottomAlignText, tt.animation.(Option))
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", tt.name, err)
+		}
+		var got *image.RGBA
+		for f := 0; ; f++ {
+			if f > 1000 {
+				t.Fatalf("%s: animation never finished", tt.name)
+			}
+			got = image.NewRGBA(want.Rect)
+			finished, ui, _, err := tb.DrawNextFrame(got)
+			if err != nil {
+				t.Fatalf("%s: Draw next frame error: %v", tt.name, err)
+			}
+			if finished && ui {
+				break
+			}
+		}
+		if !bytes.Equal(want.Pix, got.Pix) {
+			t.Errorf("%s: expected the last frame to match the page drawn without animation", tt.name)
+		}
+	}
+}
@@ -1,10 +1,16 @@
 package rpgtextbox
 
 import (
+	"fmt"
 	"github.com/arran4/golang-wordwrap"
+	"github.com/rivo/uniseg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
 	"image"
 	"image/color"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // AnimationMode interface defining the optional animation. Used as an Option
@@ -18,9 +24,48 @@ type AnimationMode interface {
 	// To determine if you're at the end the only way of doing it as of writing is to wait for; lastPage = true,
 	// userInputAccepted = false, wait = -1
 	DrawOption(target wordwrap.Image) (lastPage bool, userInputAccepted bool, wait time.Duration, err error)
+	// Skip completes the animation of the current page, the next DrawOption draws the whole page and accepts user input.
+	// Does nothing if the page is already complete and waiting for user input
+	Skip()
+	// reset returns the animation to the start of a page, used when the page is changed out from under it
+	reset()
+	// snapshot captures the animation's position for State
+	snapshot() *AnimationState
+	// restore returns the animation to a position captured by snapshot
+	restore(as *AnimationState) error
 	Option
 }
 
+// Names used to identify the animation in an AnimationState
+const (
+	fadeAnimationName           = "fade"
+	boxByBoxAnimationName       = "box-by-box"
+	letterByLetterAnimationName = "letter-by-letter"
+)
+
+// DefaultPunctuation is the punctuation NewBoxByBoxAnimation and NewLetterByLetterAnimation add PunctuationDelay after
+const DefaultPunctuation = ".,!?"
+
+// DefaultPunctuationDelay is a suggested PunctuationDelay, the animations have none unless it is set
+const DefaultPunctuationDelay = 300 * time.Millisecond
+
+// tagWaitTime adjusts the wait after text from box is revealed. A Pause box replaces the wait, a Speed box divides it,
+// and punctuationDelay is added if the text ends in one of punctuation.
+func tagWaitTime(wait time.Duration, box wordwrap.Box, text string, punctuation string, punctuationDelay time.Duration) time.Duration {
+	switch id := boxID(box).(type) {
+	case Pause:
+		return time.Duration(id)
+	case Speed:
+		if id > 0 {
+			wait = time.Duration(float64(wait) / float64(id))
+		}
+	}
+	if r, _ := utf8.DecodeLastRuneInString(text); r != utf8.RuneError && strings.ContainsRune(punctuation, r) {
+		wait += punctuationDelay
+	}
+	return wait
+}
+
 // AlphaSourceImageMapper is a draw.Image compatible source image, that allows an image to fade.
 type AlphaSourceImageMapper struct {
 	// original image
@@ -66,7 +111,7 @@ type FadeAnimation struct {
 	duration  time.Duration
 	steps     int
 	step      int
-	layout    *SimpleLayout
+	layout    Layout
 	page      *Page
 }
 
@@ -80,7 +125,7 @@ type FadeAnimation struct {
 // userInputAccepted = false, wait = -1
 func (f *FadeAnimation) DrawOption(target wordwrap.Image) (finished bool, userInputAccepted bool, waitTime time.Duration, err error) {
 	if f.layout == nil {
-		f.layout, f.page, err = f.tb.getNextPage(target.Bounds())
+		f.layout, f.page, err = f.tb.animationPage(target.Bounds(), f.page)
 		if err != nil {
 			return
 		}
@@ -118,12 +163,54 @@ func (f *FadeAnimation) DrawOption(target wordwrap.Image) (finished bool, userIn
 		f.fadeState = FadeIn
 		f.step = 0
 		f.layout = nil
+		f.page = nil
 	} else {
 		f.step++
 	}
 	return
 }
 
+// Skip completes the fade in of the current page, a page which is fading out is unaffected
+func (f *FadeAnimation) Skip() {
+	if f.fadeState == FadeIn {
+		f.step = f.steps
+	}
+}
+
+// reset returns the animation to the start of a page
+func (f *FadeAnimation) reset() {
+	f.fadeState = FadeIn
+	f.step = 0
+	f.layout = nil
+	f.page = nil
+}
+
+// snapshot captures the animation's position for State
+func (f *FadeAnimation) snapshot() *AnimationState {
+	return &AnimationState{
+		Type:      fadeAnimationName,
+		InPage:    f.page != nil,
+		FadeState: f.fadeState,
+		Step:      f.step,
+	}
+}
+
+// restore returns the animation to a position captured by snapshot
+func (f *FadeAnimation) restore(as *AnimationState) error {
+	if as.Type != fadeAnimationName {
+		return fmt.Errorf("can not restore a %s animation state into a %s animation", as.Type, fadeAnimationName)
+	}
+	page, err := f.tb.animationStatePage(as)
+	if err != nil {
+		return err
+	}
+	f.reset()
+	f.page = page
+	f.fadeState = as.FadeState
+	f.step = as.Step
+	return nil
+}
+
 // apply Set the location when used as an Option
 func (f *FadeAnimation) apply(box *TextBox) {
 	f.tb = box
@@ -146,10 +233,21 @@ func NewFadeAnimation() *FadeAnimation {
 type BoxByBoxAnimation struct {
 	tb        *TextBox
 	boxNumber int
-	layout    *SimpleLayout
+	layout    Layout
 	page      *Page
+	// revealed is the most recent box drawn in this frame
+	revealed wordwrap.Box
+	// skip is set when Skip is called before the first page has been fetched
+	skip bool
 	// The function to calculate the wait time between each box
 	WaitTimeFunc func(*BoxByBoxAnimation) time.Duration
+	// BoxWaitTimeFunc if set replaces the default timing (see WaitTime) and is given the box just revealed, which is nil
+	// if nothing was
+	BoxWaitTimeFunc func(byb *BoxByBoxAnimation, box wordwrap.Box) time.Duration
+	// PunctuationDelay is added to the wait after a box ending in one of Punctuation
+	PunctuationDelay time.Duration
+	// Punctuation is the letters PunctuationDelay applies to
+	Punctuation string
 }
 
 // DrawOption draws with options.. Controls the drawing process to add extra frames, a wait time and more
@@ -162,7 +260,7 @@ type BoxByBoxAnimation struct {
 // userInputAccepted = false, wait = -1
 func (byb *BoxByBoxAnimation) DrawOption(target wordwrap.Image) (finished bool, userInputAccepted bool, waitTime time.Duration, err error) {
 	if byb.layout == nil {
-		byb.layout, byb.page, err = byb.tb.getNextPage(target.Bounds())
+		byb.layout, byb.page, err = byb.tb.animationPage(target.Bounds(), byb.page)
 		if err != nil {
 			return
 		}
@@ -171,10 +269,14 @@ func (byb *BoxByBoxAnimation) DrawOption(target wordwrap.Image) (finished bool,
 			waitTime = -1
 			return
 		}
+		if byb.skip {
+			byb.Skip()
+		}
 	}
 	var done bool
 	var opts []wordwrap.DrawOption
 
+	byb.revealed = nil
 	if byb.boxNumber != byb.page.boxCount {
 		opts = append(opts, wordwrap.BoxDrawMap(func(box wordwrap.Box, drawConfig *wordwrap.DrawConfig, stats *wordwrap.BoxPositionStats) wordwrap.Box {
 			if stats.PageBoxOffset == byb.boxNumber {
@@ -183,6 +285,9 @@ func (byb *BoxByBoxAnimation) DrawOption(target wordwrap.Image) (finished bool,
 				}
 			}
 			if stats.PageBoxOffset < byb.boxNumber {
+				if !box.Whitespace() {
+					byb.revealed = box
+				}
 				return box
 			}
 			return nil
@@ -196,18 +301,77 @@ func (byb *BoxByBoxAnimation) DrawOption(target wordwrap.Image) (finished bool,
 		userInputAccepted = true
 		byb.boxNumber = 0
 		byb.layout = nil
+		byb.page = nil
 		waitTime = -1
 	} else {
-		if byb.WaitTimeFunc != nil {
-			waitTime = byb.WaitTimeFunc(byb)
+		if byb.BoxWaitTimeFunc != nil {
+			waitTime = byb.BoxWaitTimeFunc(byb, byb.revealed)
 		} else {
-			waitTime = time.Second / 10
+			waitTime = byb.WaitTime(byb.revealed)
 		}
 		byb.boxNumber++
 	}
 	return
 }
 
+// WaitTime is the default time to wait after box is revealed. It starts with WaitTimeFunc, which is replaced by the
+// duration of a Pause box, divided by the Speed of a box and has PunctuationDelay added if box ends in punctuation.
+func (byb *BoxByBoxAnimation) WaitTime(box wordwrap.Box) time.Duration {
+	wait := time.Second / 10
+	if byb.WaitTimeFunc != nil {
+		wait = byb.WaitTimeFunc(byb)
+	}
+	var text string
+	if box != nil {
+		text = box.TextValue()
+	}
+	return tagWaitTime(wait, box, text, byb.Punctuation, byb.PunctuationDelay)
+}
+
+// Skip reveals every box of the current page, it does nothing once the page is complete
+func (byb *BoxByBoxAnimation) Skip() {
+	if byb.page == nil {
+		// Before the first page is fetched the skip applies to it, afterwards the page is complete and waiting for input
+		byb.skip = byb.tb.nextPage == 0
+		return
+	}
+	byb.skip = false
+	byb.boxNumber = byb.page.boxCount + 1
+}
+
+// reset returns the animation to the start of a page
+func (byb *BoxByBoxAnimation) reset() {
+	byb.boxNumber = 0
+	byb.skip = false
+	byb.revealed = nil
+	byb.layout = nil
+	byb.page = nil
+}
+
+// snapshot captures the animation's position for State
+func (byb *BoxByBoxAnimation) snapshot() *AnimationState {
+	return &AnimationState{
+		Type:      boxByBoxAnimationName,
+		InPage:    byb.page != nil,
+		BoxNumber: byb.boxNumber,
+	}
+}
+
+// restore returns the animation to a position captured by snapshot
+func (byb *BoxByBoxAnimation) restore(as *AnimationState) error {
+	if as.Type != boxByBoxAnimationName {
+		return fmt.Errorf("can not restore a %s animation state into a %s animation", as.Type, boxByBoxAnimationName)
+	}
+	page, err := byb.tb.animationStatePage(as)
+	if err != nil {
+		return err
+	}
+	byb.reset()
+	byb.page = page
+	byb.boxNumber = as.BoxNumber
+	return nil
+}
+
 // apply Set the location when used as an Option
 func (byb *BoxByBoxAnimation) apply(box *TextBox) {
 	byb.tb = box
@@ -224,18 +388,102 @@ func NewBoxByBoxAnimation() *BoxByBoxAnimation {
 		WaitTimeFunc: func(byb *BoxByBoxAnimation) time.Duration {
 			return time.Second / 10
 		},
+		Punctuation: DefaultPunctuation,
+	}
+}
+
+// graphemes splits text into grapheme clusters, which are the letters a reader sees rather than bytes or runes
+func graphemes(text string) []string {
+	var letters []string
+	g := uniseg.NewGraphemes(text)
+	for g.Next() {
+		letters = append(letters, g.Str())
+	}
+	return letters
+}
+
+// partialBox draws only the left width pixels of the box it wraps. Used to reveal part of a box without losing its font,
+// color or effects.
+type partialBox struct {
+	wordwrap.Box
+	width int
+}
+
+// newPartialBox creates a partialBox which shows the prefix of box's text
+func newPartialBox(box wordwrap.Box, prefix string) *partialBox {
+	tb, inset := textBox(box)
+	var face font.Face
+	if tb != nil && tb.FontDrawer() != nil {
+		face = tb.FontDrawer().Face
+	} else if box.FontDrawer() != nil {
+		face = box.FontDrawer().Face
+	}
+	if face == nil {
+		return &partialBox{Box: box, width: box.AdvanceRect().Ceil()}
+	}
+	return &partialBox{Box: box, width: (inset + font.MeasureString(face, prefix)).Ceil()}
+}
+
+// DrawBox draws the wrapped box clipped to the width
+func (pb *partialBox) DrawBox(i wordwrap.Image, y fixed.Int26_6, dc *wordwrap.DrawConfig) {
+	r := i.Bounds()
+	if r.Min.X+pb.width < r.Max.X {
+		r.Max.X = r.Min.X + pb.width
+	}
+	pb.Box.DrawBox(i.SubImage(r).(wordwrap.Image), y, dc)
+}
+
+// textBox finds the text box inside any decorations of a box and how far from the left of the box the text starts
+func textBox(b wordwrap.Box) (*wordwrap.SimpleTextBox, fixed.Int26_6) {
+	var inset fixed.Int26_6
+	for {
+		switch bb := b.(type) {
+		case *wordwrap.SimpleTextBox:
+			return bb, inset
+		case *wordwrap.DecorationBox:
+			inset += fixed.I(bb.Margin.Min.X.Ceil() + bb.Padding.Min.X.Ceil())
+			b = bb.Box
+		case *wordwrap.IDBox:
+			b = bb.Box
+		case *wordwrap.EffectBox:
+			b = bb.Box
+		case *wordwrap.BackgroundBox:
+			b = bb.Box
+		case *wordwrap.AlignedBox:
+			b = bb.Box
+		case *wordwrap.MinSizeBox:
+			b = bb.Box
+		case *wordwrap.LineBreakBox:
+			b = bb.Box
+		default:
+			return nil, inset
+		}
 	}
 }
 
-// LetterByLetterAnimation is an animation style in which each non-whitespace letter comes into visibility one by one
+// LetterByLetterAnimation is an animation style in which each non-whitespace letter comes into visibility one by one.
+// Letters are grapheme clusters so accents, CJK and emoji are revealed whole, and each box keeps its own styling.
 type LetterByLetterAnimation struct {
 	tb           *TextBox
 	boxNumber    int
 	letterNumber int
-	layout       *SimpleLayout
+	layout       Layout
 	page         *Page
+	// revealed is the box a letter was revealed from in this frame
+	revealed wordwrap.Box
+	// revealedLetter is the letter revealed in this frame
+	revealedLetter string
+	// skip is set when Skip is called before the first page has been fetched
+	skip bool
 	// The function to calculate the wait time between each box
 	WaitTimeFunc func(*LetterByLetterAnimation) time.Duration
+	// LetterWaitTimeFunc if set replaces the default timing (see WaitTime) and is given the box and the letter just
+	// revealed, box is nil if nothing was revealed and letter is empty for boxes without text such as a Pause
+	LetterWaitTimeFunc func(lyl *LetterByLetterAnimation, box wordwrap.Box, letter string) time.Duration
+	// PunctuationDelay is added to the wait after one of Punctuation is revealed
+	PunctuationDelay time.Duration
+	// Punctuation is the letters PunctuationDelay applies to
+	Punctuation string
 }
 
 // DrawOption draws with options.. Controls the drawing process to add extra frames, a wait time and more
@@ -248,7 +496,7 @@ type LetterByLetterAnimation struct {
 // userInputAccepted = false, wait = -1
 func (lyl *LetterByLetterAnimation) DrawOption(target wordwrap.Image) (finished bool, userInputAccepted bool, waitTime time.Duration, err error) {
 	if lyl.layout == nil {
-		lyl.layout, lyl.page, err = lyl.tb.getNextPage(target.Bounds())
+		lyl.layout, lyl.page, err = lyl.tb.animationPage(target.Bounds(), lyl.page)
 		if err != nil {
 			return
 		}
@@ -257,21 +505,35 @@ func (lyl *LetterByLetterAnimation) DrawOption(target wordwrap.Image) (finished
 			waitTime = -1
 			return
 		}
+		if lyl.skip {
+			lyl.Skip()
+		}
 	}
 	var done bool
 	var opts []wordwrap.DrawOption
 
+	lyl.revealed = nil
+	lyl.revealedLetter = ""
 	if lyl.boxNumber != lyl.page.boxCount {
 		opts = append(opts, wordwrap.BoxDrawMap(func(box wordwrap.Box, drawConfig *wordwrap.DrawConfig, stats *wordwrap.BoxPositionStats) wordwrap.Box {
 			if stats.PageBoxOffset == lyl.boxNumber {
+				letters := graphemes(box.TextValue())
 				if lyl.letterNumber == 0 {
 					lyl.letterNumber++
 					return nil
-				} else if box.Whitespace() || lyl.letterNumber >= len(box.TextValue()) {
+				} else if box.Whitespace() || lyl.letterNumber >= len(letters) {
+					if !box.Whitespace() {
+						lyl.revealed = box
+						if len(letters) > 0 {
+							lyl.revealedLetter = letters[len(letters)-1]
+						}
+					}
 					lyl.boxNumber++
 					lyl.letterNumber = 0
-				} else if lyl.letterNumber <= len(box.TextValue()) {
-					b, _ := wordwrap.NewSimpleTextBox(box.FontDrawer(), box.TextValue()[:lyl.letterNumber])
+				} else {
+					lyl.revealed = box
+					lyl.revealedLetter = letters[lyl.letterNumber-1]
+					b := newPartialBox(box, strings.Join(letters[:lyl.letterNumber], ""))
 					lyl.letterNumber++
 					return b
 				}
@@ -291,17 +553,77 @@ func (lyl *LetterByLetterAnimation) DrawOption(target wordwrap.Image) (finished
 		lyl.boxNumber = 0
 		lyl.letterNumber = 0
 		lyl.layout = nil
+		lyl.page = nil
 		waitTime = -1
 	} else {
-		if lyl.WaitTimeFunc != nil {
-			waitTime = lyl.WaitTimeFunc(lyl)
+		if lyl.LetterWaitTimeFunc != nil {
+			waitTime = lyl.LetterWaitTimeFunc(lyl, lyl.revealed, lyl.revealedLetter)
 		} else {
-			waitTime = time.Second / 10
+			waitTime = lyl.WaitTime(lyl.revealed, lyl.revealedLetter)
 		}
 	}
 	return
 }
 
+// WaitTime is the default time to wait after letter is revealed from box. It starts with WaitTimeFunc, which is replaced
+// by the duration of a Pause box, divided by the Speed of a box and has PunctuationDelay added if letter is punctuation.
+func (lyl *LetterByLetterAnimation) WaitTime(box wordwrap.Box, letter string) time.Duration {
+	wait := time.Second / 10
+	if lyl.WaitTimeFunc != nil {
+		wait = lyl.WaitTimeFunc(lyl)
+	}
+	return tagWaitTime(wait, box, letter, lyl.Punctuation, lyl.PunctuationDelay)
+}
+
+// Skip reveals every letter of the current page, it does nothing once the page is complete
+func (lyl *LetterByLetterAnimation) Skip() {
+	if lyl.page == nil {
+		// Before the first page is fetched the skip applies to it, afterwards the page is complete and waiting for input
+		lyl.skip = lyl.tb.nextPage == 0
+		return
+	}
+	lyl.skip = false
+	lyl.boxNumber = lyl.page.boxCount
+	lyl.letterNumber = 0
+}
+
+// reset returns the animation to the start of a page
+func (lyl *LetterByLetterAnimation) reset() {
+	lyl.boxNumber = 0
+	lyl.skip = false
+	lyl.letterNumber = 0
+	lyl.revealed = nil
+	lyl.revealedLetter = ""
+	lyl.layout = nil
+	lyl.page = nil
+}
+
+// snapshot captures the animation's position for State
+func (lyl *LetterByLetterAnimation) snapshot() *AnimationState {
+	return &AnimationState{
+		Type:         letterByLetterAnimationName,
+		InPage:       lyl.page != nil,
+		BoxNumber:    lyl.boxNumber,
+		LetterNumber: lyl.letterNumber,
+	}
+}
+
+// restore returns the animation to a position captured by snapshot
+func (lyl *LetterByLetterAnimation) restore(as *AnimationState) error {
+	if as.Type != letterByLetterAnimationName {
+		return fmt.Errorf("can not restore a %s animation state into a %s animation", as.Type, letterByLetterAnimationName)
+	}
+	page, err := lyl.tb.animationStatePage(as)
+	if err != nil {
+		return err
+	}
+	lyl.reset()
+	lyl.page = page
+	lyl.boxNumber = as.BoxNumber
+	lyl.letterNumber = as.LetterNumber
+	return nil
+}
+
 // apply Set the location when used as an Option
 func (lyl *LetterByLetterAnimation) apply(box *TextBox) {
 	lyl.tb = box
@@ -318,5 +640,6 @@ func NewLetterByLetterAnimation() *LetterByLetterAnimation {
 		WaitTimeFunc: func(byb *LetterByLetterAnimation) time.Duration {
 			return time.Second / 10
 		},
+		Punctuation: DefaultPunctuation,
 	}
 }
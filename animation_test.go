@@ -0,0 +1,283 @@
+package rpgtextbox
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	wordwrap "github.com/arran4/golang-wordwrap"
+)
+
+func TestLetterByLetterTiming(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	m, err := NewMarkup()
+	if err != nil {
+		t.Fatalf("NewMarkup error: %v", err)
+	}
+	args, err := m.Parse("Hi.{pause=1s} {speed=0.5}ok{/speed} bye")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	a := NewLetterByLetterAnimation()
+	a.PunctuationDelay = DefaultPunctuationDelay
+	type reveal struct {
+		letter string
+		wait   time.Duration
+	}
+	var reveals []reveal
+	a.LetterWaitTimeFunc = func(lyl *LetterByLetterAnimation, box wordwrap.Box, letter string) time.Duration {
+		wait := lyl.WaitTime(box, letter)
+		if box != nil {
+			reveals = append(reveals, reveal{letter, wait})
+		}
+		return wait
+	}
+	size := image.Pt(400, 100)
+	tb, err := NewRichTextBox(theme, append(args, size, a)...)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for {
+		_, ui, _, err := tb.DrawNextFrame(i)
+		if err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+		if ui {
+			break
+		}
+	}
+	want := []reveal{
+		{"H", 100 * time.Millisecond},
+		{"i", 100 * time.Millisecond},
+		{".", 400 * time.Millisecond},
+		{"", time.Second},
+		{"o", 200 * time.Millisecond},
+		{"k", 200 * time.Millisecond},
+		{"b", 100 * time.Millisecond},
+	}
+	if len(reveals) < len(want) {
+		t.Fatalf("Got reveals %v want %v", reveals, want)
+	}
+	for n := range want {
+		if reveals[n] != want[n] {
+			t.Errorf("Reveal %d = %v want %v", n, reveals[n], want[n])
+		}
+	}
+}
+
+func TestBoxByBoxTiming(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	tests := []struct {
+		name             string
+		punctuationDelay time.Duration
+		want             []time.Duration
+	}{
+		{"Default", 0, []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}},
+		{"PunctuationDelay", DefaultPunctuationDelay, []time.Duration{400 * time.Millisecond, 100 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		a := NewBoxByBoxAnimation()
+		if tt.punctuationDelay != 0 {
+			a.PunctuationDelay = tt.punctuationDelay
+		}
+		var waits []time.Duration
+		a.BoxWaitTimeFunc = func(byb *BoxByBoxAnimation, box wordwrap.Box) time.Duration {
+			wait := byb.WaitTime(box)
+			if box != nil {
+				waits = append(waits, wait)
+			}
+			return wait
+		}
+		size := image.Pt(400, 100)
+		tb, err := NewSimpleTextBox(theme, "Hello, world again", size, a)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", tt.name, err)
+		}
+		i := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		for {
+			_, ui, _, err := tb.DrawNextFrame(i)
+			if err != nil {
+				t.Fatalf("%s: Draw next frame error: %v", tt.name, err)
+			}
+			if ui {
+				break
+			}
+		}
+		if len(waits) < 2 || waits[0] != tt.want[0] || waits[1] != tt.want[1] {
+			t.Errorf("%s: Got waits %v want %v", tt.name, waits, tt.want)
+		}
+	}
+}
+
+func TestSkip(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(300, 100)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box."
+	want := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	static, err := NewSimpleTextBox(theme, text, size)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	if _, err := static.DrawNextPageFrame(want); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	tests := []struct {
+		name      string
+		animation func() AnimationMode
+	}{
+		{"FadeAnimation", func() AnimationMode { return NewFadeAnimation() }},
+		{"BoxByBoxAnimation", func() AnimationMode { return NewBoxByBoxAnimation() }},
+		{"LetterByLetterAnimation", func() AnimationMode { return NewLetterByLetterAnimation() }},
+	}
+	for _, tt := range tests {
+		for _, frames := range []int{0, 3} {
+			tb, err := NewSimpleTextBox(theme, text, size, tt.animation())
+			if err != nil {
+				t.Fatalf("Error creating text box: %v", err)
+			}
+			for f := 0; f < frames; f++ {
+				if _, ui, _, err := tb.DrawNextFrame(image.NewRGBA(want.Rect)); err != nil {
+					t.Fatalf("Draw next frame error: %v", err)
+				} else if ui {
+					t.Fatalf("%s: user input accepted before skipping", tt.name)
+				}
+			}
+			tb.Skip()
+			got := image.NewRGBA(want.Rect)
+			if _, ui, _, err := tb.DrawNextFrame(got); err != nil {
+				t.Fatalf("Draw next frame error: %v", err)
+			} else if !ui {
+				t.Errorf("%s after %d frames: expected user input to be accepted after Skip", tt.name, frames)
+			}
+			if !bytes.Equal(want.Pix, got.Pix) {
+				t.Errorf("%s after %d frames: expected the whole page to be drawn after Skip", tt.name, frames)
+			}
+			if tb.CurrentPage() != 0 {
+				t.Errorf("%s after %d frames: CurrentPage() = %d want 0", tt.name, frames, tb.CurrentPage())
+			}
+		}
+	}
+}
+
+func TestSkipWaitingForInput(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(300, 100)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box."
+	tests := []struct {
+		name      string
+		animation func() AnimationMode
+	}{
+		{"FadeAnimation", func() AnimationMode { return NewFadeAnimation() }},
+		{"BoxByBoxAnimation", func() AnimationMode { return NewBoxByBoxAnimation() }},
+		{"LetterByLetterAnimation", func() AnimationMode { return NewLetterByLetterAnimation() }},
+		{"ScrollAnimation", func() AnimationMode { return NewScrollAnimation() }},
+	}
+	for _, tt := range tests {
+		tb, err := NewSimpleTextBox(theme, text, size, tt.animation())
+		if err != nil {
+			t.Fatalf("Error creating text box: %v", err)
+		}
+		for f := 0; ; f++ {
+			if f > 1000 {
+				t.Fatalf("%s: user input never accepted", tt.name)
+			}
+			_, ui, _, err := tb.DrawNextFrame(image.NewRGBA(image.Rectangle{Max: size}))
+			if err != nil {
+				t.Fatalf("Draw next frame error: %v", err)
+			}
+			if ui {
+				break
+			}
+		}
+		tb.Skip()
+		if _, ui, _, err := tb.DrawNextFrame(image.NewRGBA(image.Rectangle{Max: size})); err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		} else if ui {
+			t.Errorf("%s: expected Skip while waiting for input not to skip the next page", tt.name)
+		}
+	}
+}
+
+func TestLetterByLetterGraphemes(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	a := NewLetterByLetterAnimation()
+	var letters []string
+	a.LetterWaitTimeFunc = func(lyl *LetterByLetterAnimation, box wordwrap.Box, letter string) time.Duration {
+		if box != nil {
+			letters = append(letters, letter)
+		}
+		return lyl.WaitTime(box, letter)
+	}
+	size := image.Pt(400, 100)
+	tb, err := NewSimpleTextBox(theme, "Cafe\u0301 日本 👍🏽 end", size, a)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for {
+		_, ui, _, err := tb.DrawNextFrame(i)
+		if err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+		if ui {
+			break
+		}
+	}
+	want := []string{"C", "a", "f", "e\u0301", "日", "本", "👍🏽", "e", "n"}
+	if len(letters) != len(want) {
+		t.Fatalf("Got letters %q want %q", letters, want)
+	}
+	for n := range want {
+		if letters[n] != want[n] {
+			t.Errorf("Letter %d = %q want %q", n, letters[n], want[n])
+		}
+	}
+}
+
+func TestLetterByLetterKeepsStyle(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(400, 100)
+	tb, err := NewRichTextBox(theme, wordwrap.Highlight(color.RGBA{R: 255, A: 255}, "Highlighted"), size, NewLetterByLetterAnimation())
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for f := 0; f < 2; f++ {
+		if _, _, _, err := tb.DrawNextFrame(i); err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+	}
+	red := 0
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			if r, g, b, _ := i.At(x, y).RGBA(); r > 0x8000 && g < 0x4000 && b < 0x4000 {
+				red++
+			}
+		}
+	}
+	if red == 0 {
+		t.Errorf("Expected the partially revealed text to still be highlighted")
+	}
+}
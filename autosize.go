@@ -0,0 +1,164 @@
+package rpgtextbox
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	wordwrap "github.com/arran4/golang-wordwrap"
+)
+
+// AutoSize is an Option which measures the wrapped text and chooses the smallest text box it fits in, the destSize given
+// to NewRichTextBox is ignored. The size allows for the frame, avatar, name, chevron and choices. Draw into an image of
+// Size.
+type AutoSize struct {
+	// MaxWidth is the widest the text box can be, text is wrapped to fit
+	MaxWidth int
+	// MinAspect if positive is the smallest width / height allowed, the box is widened (up to MaxWidth) to meet it
+	MinAspect float64
+	// MaxAspect if positive is the largest width / height allowed, the text is wrapped narrower or the box made taller to
+	// meet it
+	MaxAspect float64
+	// size is the size chosen
+	size image.Point
+}
+
+// NewAutoSize creates an AutoSize with no aspect limits
+func NewAutoSize(maxWidth int) *AutoSize {
+	return &AutoSize{
+		MaxWidth: maxWidth,
+	}
+}
+
+// apply Set the auto size when used as an Option
+func (as *AutoSize) apply(box *TextBox) {
+	box.autoSize = as
+}
+
+// Size is the size chosen for the text box, 0 until the text box has been created
+func (as *AutoSize) Size() image.Point {
+	return as.size
+}
+
+// measure finds the size of the text box for the word wrapper's arguments
+func (as *AutoSize) measure(tb *TextBox, args []interface{}) (image.Point, error) {
+	if as.MaxWidth <= 0 {
+		return image.Point{}, errors.New("auto size requires a maximum width")
+	}
+	minSize := tb.minFrameSize()
+	size, err := as.fit(tb, args, as.MaxWidth)
+	if err != nil {
+		return image.Point{}, err
+	}
+	if as.MaxAspect > 0 && aspect(size) > as.MaxAspect {
+		// Wrap narrower to make the box taller, the widest width that meets the limit changes the text the least
+		lo, hi := minSize.X, size.X-1
+		for lo <= hi {
+			mid := (lo + hi) / 2
+			s, err := as.fit(tb, args, mid)
+			if err == nil && aspect(s) <= as.MaxAspect {
+				lo, size = mid+1, s
+			} else {
+				hi = mid - 1
+			}
+		}
+		if aspect(size) > as.MaxAspect {
+			size.Y = int(math.Ceil(float64(size.X) / as.MaxAspect))
+		}
+	}
+	if as.MinAspect > 0 && aspect(size) < as.MinAspect {
+		size.X = min(int(math.Ceil(float64(size.Y)*as.MinAspect)), max(as.MaxWidth, size.X))
+	}
+	as.size = size
+	return size, nil
+}
+
+// fit returns the smallest size which holds the text wrapped to fit inside a box width wide
+func (as *AutoSize) fit(tb *TextBox, args []interface{}, width int) (image.Point, error) {
+	minSize := tb.minFrameSize()
+	dest := image.Rectangle{Max: image.Pt(max(width, minSize.X), math.MaxInt32/2)}
+	l, err := tb.newLayout(dest)
+	if err != nil {
+		return image.Point{}, err
+	}
+	if l.TextRect().Dx() <= 0 {
+		return image.Point{}, fmt.Errorf("no room for text in a width of %d", width)
+	}
+	content, err := contentSize(args, l.TextRect().Dx())
+	if err != nil {
+		return image.Point{}, err
+	}
+	content.X = max(content.X, tb.minContentWidth())
+	size := content.Add(dest.Size().Sub(l.TextRect().Size()))
+	size = image.Pt(max(size.X, minSize.X), max(size.Y, minSize.Y))
+	// The layout's borders can depend on its size (such as a scaled avatar) so grow until the content fits
+	for i := 0; i < 10; i++ {
+		l, err := tb.newLayout(image.Rectangle{Max: size})
+		if err != nil {
+			return image.Point{}, err
+		}
+		tr := l.TextRect().Size()
+		grow := image.Pt(max(content.X-tr.X, 0), max(content.Y-tr.Y, 0))
+		if tb.avatarLocation != NoAvatar && (tb.avatarFit == NoAvatarFit || tb.avatarFit == CenterAvatar || tb.avatarFit == IntegerScale) {
+			room := l.CenterRect()
+			if tb.avatarLocation.outside() {
+				room = l.AvatarRect()
+			}
+			grow.Y = max(grow.Y, tb.Avatar().Bounds().Dy()-room.Dy())
+		}
+		if grow == (image.Point{}) {
+			return size, nil
+		}
+		size = size.Add(grow)
+	}
+	return size, nil
+}
+
+// contentSize wraps the text to width and returns the size of the lines
+func contentSize(args []interface{}, width int) (image.Point, error) {
+	w := wordwrap.NewRichWrapper(args...)
+	ls, _, err := w.TextToRect(image.Rect(0, 0, width, math.MaxInt32/2))
+	if err != nil {
+		return image.Point{}, err
+	}
+	var size image.Point
+	for _, l := range ls {
+		s := l.Size()
+		size.X = max(size.X, s.Dx())
+		size.Y += s.Dy()
+	}
+	return size, nil
+}
+
+// minContentWidth is the width of the widest thing other than the text that has to fit in the text area
+func (tb *TextBox) minContentWidth() int {
+	width := 0
+	if tb.nameBox != nil && tb.namePosition != NoName {
+		width = tb.nameTagSize().X
+	}
+	switch tb.moreChevronLocation {
+	case NoMoreChevron, TextEndChevron:
+	default:
+		width = max(width, tb.theme.Chevron().Bounds().Dx())
+	}
+	if tb.choiceMenu != nil {
+		width = max(width, tb.choiceMenu.width(tb))
+	}
+	return width
+}
+
+// minFrameSize is the size of the frame's borders, the smallest the frame can be drawn
+func (tb *TextBox) minFrameSize() image.Point {
+	if f, ok := tb.theme.(theme.Frame); ok {
+		fb := f.Frame().Bounds()
+		return fb.Size().Sub(f.FrameCenter().Size()).Add(image.Pt(1, 1))
+	}
+	return image.Pt(1, 1)
+}
+
+// aspect is the width / height of size
+func aspect(size image.Point) float64 {
+	return float64(size.X) / float64(max(size.Y, 1))
+}
@@ -0,0 +1,54 @@
+package rpgtextbox
+
+import (
+	"image"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+)
+
+func TestAutoSize(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	long := "This is a long piece of text which is going to need several lines to display in a tooltip."
+	tests := []struct {
+		name    string
+		text    string
+		as      *AutoSize
+		options []Option
+	}{
+		{"Short", "Hi!", NewAutoSize(400), nil},
+		{"Long", long, NewAutoSize(400), nil},
+		{"MaxAspect", long, &AutoSize{MaxWidth: 400, MaxAspect: 2}, nil},
+		{"MinAspect", "Hi!", &AutoSize{MaxWidth: 400, MinAspect: 3}, nil},
+		{"AvatarNameChevron", long, NewAutoSize(500), []Option{LeftAvatar, CenterAvatar, Name("Someone with a long name"), NameTopLeftAboveTextInFrame, CenterBottomInsideTextFrame}},
+	}
+	sizes := map[string]image.Point{}
+	for _, tt := range tests {
+		tb, err := NewSimpleTextBox(theme, tt.text, image.Point{}, append([]Option{tt.as}, tt.options...)...)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", tt.name, err)
+		}
+		size := tt.as.Size()
+		if size.X <= 0 || size.X > tt.as.MaxWidth || size.Y <= 0 {
+			t.Fatalf("%s: invalid size %v", tt.name, size)
+		}
+		if pages, err := tb.CalculateAllPages(size); err != nil {
+			t.Fatalf("%s: Calculate pages error: %v", tt.name, err)
+		} else if pages != 1 {
+			t.Errorf("%s: expected the text to fit on one page of %v got %d pages", tt.name, size, pages)
+		}
+		if tt.as.MaxAspect > 0 && aspect(size) > tt.as.MaxAspect {
+			t.Errorf("%s: aspect of %v is more than %v", tt.name, size, tt.as.MaxAspect)
+		}
+		if tt.as.MinAspect > 0 && aspect(size) < tt.as.MinAspect {
+			t.Errorf("%s: aspect of %v is less than %v", tt.name, size, tt.as.MinAspect)
+		}
+		sizes[tt.name] = size
+	}
+	if sizes["Short"].X >= sizes["Long"].X || sizes["Short"].Y >= sizes["Long"].Y {
+		t.Errorf("Expected short text %v to get a smaller box than long text %v", sizes["Short"], sizes["Long"])
+	}
+}
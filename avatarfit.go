@@ -0,0 +1,84 @@
+package rpgtextbox
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// AvatarAnchor defines where in its space an avatar that doesn't fill it (or is cropped) sits vertically, it applies
+// to the CenterAvatar, Contain, Cover and IntegerScale fits.
+type AvatarAnchor int
+
+const (
+	// AvatarAnchorCenter centers the avatar, the default.
+	AvatarAnchorCenter AvatarAnchor = iota
+	// AvatarAnchorTop puts the top of the avatar at the top of its space, cropping from the bottom.
+	AvatarAnchorTop
+	// AvatarAnchorBottom puts the bottom of the avatar at the bottom of its space, for portraits cut off at the chest.
+	AvatarAnchorBottom
+)
+
+// apply implements the Option interface.
+func (aa AvatarAnchor) apply(box *TextBox) {
+	box.avatarAnchor = aa
+}
+
+// place positions something size big inside r, centered horizontally and vertically by the anchor.
+func (aa AvatarAnchor) place(size image.Point, r image.Rectangle) image.Rectangle {
+	at := r.Min.Add(r.Size().Sub(size).Div(2))
+	switch aa {
+	case AvatarAnchorTop:
+		at.Y = r.Min.Y
+	case AvatarAnchorBottom:
+		at.Y = r.Max.Y - size.Y
+	}
+	return image.Rectangle{Min: at, Max: at.Add(size)}
+}
+
+// avatarFitSize is the size the text box's AvatarFit draws an avatar of size at in space. It can be larger than space
+// when the avatar is cropped.
+func (tb *TextBox) avatarFitSize(size, space image.Point) image.Point {
+	if size.X <= 0 || size.Y <= 0 || space.X <= 0 || space.Y <= 0 {
+		return size
+	}
+	sx, sy := float64(space.X)/float64(size.X), float64(space.Y)/float64(size.Y)
+	var scale float64
+	switch tb.avatarFit {
+	case NearestNeighbour, ApproxBiLinear, CatmullRom:
+		// Only ever shrinks
+		scale = min(sx, sy, 1)
+	case Contain:
+		scale = min(sx, sy)
+	case Cover:
+		scale = max(sx, sy)
+	case IntegerScale:
+		scale = max(float64(int(min(sx, sy))), 1)
+	default:
+		return size
+	}
+	return image.Pt(int(float64(size.X)*scale), int(float64(size.Y)*scale))
+}
+
+// avatarSlot is the size the avatar takes up in a space of size space. A covered avatar keeps its own width and fills
+// the height.
+func (tb *TextBox) avatarSlot(space image.Point) image.Point {
+	size := tb.Avatar().Bounds().Size()
+	if tb.avatarFit == Cover {
+		return image.Pt(min(size.X, space.X), space.Y)
+	}
+	return tb.avatarFitSize(size, space)
+}
+
+// avatarScaler is the interpolator the text box's AvatarFit scales with, nil if it doesn't scale
+func (tb *TextBox) avatarScaler() draw.Scaler {
+	switch tb.avatarFit {
+	case NearestNeighbour, IntegerScale:
+		return draw.NearestNeighbor
+	case ApproxBiLinear:
+		return draw.ApproxBiLinear
+	case Contain, Cover, CatmullRom:
+		return draw.CatmullRom
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package rpgtextbox
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	"golang.org/x/image/draw"
+)
+
+func TestAvatarFitSize(t *testing.T) {
+	tests := []struct {
+		fit         AvatarFit
+		size, space image.Point
+		want        image.Point
+	}{
+		{NoAvatarFit, image.Pt(10, 20), image.Pt(40, 40), image.Pt(10, 20)},
+		{NearestNeighbour, image.Pt(10, 20), image.Pt(40, 40), image.Pt(10, 20)},
+		{CatmullRom, image.Pt(30, 60), image.Pt(20, 20), image.Pt(10, 20)},
+		{Contain, image.Pt(10, 20), image.Pt(40, 40), image.Pt(20, 40)},
+		{Contain, image.Pt(30, 60), image.Pt(20, 20), image.Pt(10, 20)},
+		{Cover, image.Pt(10, 20), image.Pt(40, 40), image.Pt(40, 80)},
+		{IntegerScale, image.Pt(10, 20), image.Pt(40, 50), image.Pt(20, 40)},
+		{IntegerScale, image.Pt(30, 60), image.Pt(20, 20), image.Pt(30, 60)},
+	}
+	for _, tt := range tests {
+		tb := &TextBox{avatarFit: tt.fit}
+		if got := tb.avatarFitSize(tt.size, tt.space); got != tt.want {
+			t.Errorf("Fit %d of %v in %v = %v want %v", tt.fit, tt.size, tt.space, got, tt.want)
+		}
+	}
+}
+
+func TestAvatarFitDraw(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	red, blue := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	// A wide avatar, red on top and blue below
+	avatarImg := image.NewRGBA(image.Rect(0, 0, 10, 6))
+	draw.Draw(avatarImg, image.Rect(0, 0, 10, 3), image.NewUniform(red), image.Point{}, draw.Src)
+	draw.Draw(avatarImg, image.Rect(0, 3, 10, 6), image.NewUniform(blue), image.Point{}, draw.Src)
+	ar := image.Rect(10, 10, 50, 50)
+	tests := []struct {
+		fit    AvatarFit
+		anchor AvatarAnchor
+		// top and bottom are the rows the avatar is expected to be drawn between
+		top, bottom int
+	}{
+		{Contain, AvatarAnchorCenter, 18, 42},
+		{Contain, AvatarAnchorTop, 10, 34},
+		{Contain, AvatarAnchorBottom, 26, 50},
+		{IntegerScale, AvatarAnchorTop, 10, 34},
+		{IntegerScale, AvatarAnchorBottom, 26, 50},
+		{Cover, AvatarAnchorCenter, 10, 50},
+	}
+	for _, tt := range tests {
+		name := fmt.Sprintf("Fit%d/Anchor%d", tt.fit, tt.anchor)
+		tb, err := NewSimpleTextBox(th, "Some text", image.Pt(200, 100), LeftAvatar, tt.fit, tt.anchor)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		i := image.NewRGBA(image.Rect(0, 0, 60, 60))
+		tb.drawAvatar(i, &portraitLayout{avatarRect: ar}, avatarImg)
+		x := ar.Min.X + ar.Dx()/2
+		for y := ar.Min.Y; y < ar.Max.Y; y++ {
+			_, _, _, a := i.At(x, y).RGBA()
+			if drawn := y >= tt.top && y < tt.bottom; drawn != (a != 0) {
+				t.Errorf("%s: row %d drawn = %v want between %d and %d", name, y, a != 0, tt.top, tt.bottom)
+				break
+			}
+		}
+		if c := i.At(x, tt.top); c != red {
+			t.Errorf("%s: expected the top of the avatar at row %d got %v", name, tt.top, c)
+		}
+		if c := i.At(x, tt.bottom-1); c != blue {
+			t.Errorf("%s: expected the bottom of the avatar at row %d got %v", name, tt.bottom-1, c)
+		}
+		for _, p := range []image.Point{ar.Min.Sub(image.Pt(1, 1)), ar.Max} {
+			if _, _, _, a := i.At(p.X, p.Y).RGBA(); a != 0 {
+				t.Errorf("%s: drawn outside of the avatar rect at %v", name, p)
+			}
+		}
+	}
+	// CenterAvatar crops an avatar too big for its space by the anchor
+	for anchor, want := range map[AvatarAnchor]color.RGBA{AvatarAnchorTop: red, AvatarAnchorBottom: blue} {
+		tb, err := NewSimpleTextBox(th, "Some text", image.Pt(200, 100), LeftAvatar, CenterAvatar, anchor)
+		if err != nil {
+			t.Fatalf("Error creating text box: %v", err)
+		}
+		i := image.NewRGBA(image.Rect(0, 0, 60, 60))
+		small := image.Rect(10, 10, 20, 12)
+		tb.drawAvatar(i, &portraitLayout{avatarRect: small}, avatarImg)
+		if c := i.At(15, 11); c != want {
+			t.Errorf("Anchor%d: expected %v got %v", anchor, want, c)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package rpgtextbox
+
+import (
+	"image"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	wordwrap "github.com/arran4/golang-wordwrap"
+	"golang.org/x/image/draw"
+)
+
+// SpeechBubble is an Option which places the text box near Anchor, such as above a character's head, rather than
+// filling the image it is drawn into. The box is kept inside the image, above the anchor if there is room otherwise
+// below it. If the theme implements theme.Tail a tail is drawn from the box to the anchor.
+type SpeechBubble struct {
+	// Anchor is the point the bubble belongs to in the coordinates of the image drawn into. It can be moved between
+	// pages to follow a character
+	Anchor image.Point
+	// Size of the bubble including the frame, it is reduced if it doesn't fit in the image
+	Size image.Point
+}
+
+// TailLayout is an optional extension of Layout for layouts with an area for the SpeechBubble's tail, without it the
+// tail isn't drawn
+type TailLayout interface {
+	// TailRect is the optional area containing the speech bubble's tail.
+	TailRect() image.Rectangle
+}
+
+// NewSpeechBubble creates a SpeechBubble of size for the character at anchor
+func NewSpeechBubble(anchor, size image.Point) *SpeechBubble {
+	return &SpeechBubble{
+		Anchor: anchor,
+		Size:   size,
+	}
+}
+
+// apply Set the speech bubble when used as an Option
+func (sb *SpeechBubble) apply(box *TextBox) {
+	box.speechBubble = sb
+}
+
+// tailOf returns the theme's tail, ok is false if it doesn't have one
+func tailOf(th theme.Theme) (theme.Tail, bool) {
+	t, ok := th.(theme.Tail)
+	return t, ok && t.TailDown() != nil && t.TailUp() != nil
+}
+
+// gap is the space between the frame and the anchor taken up by the tail
+func (sb *SpeechBubble) gap(th theme.Theme) int {
+	if t, ok := tailOf(th); ok {
+		return max(t.TailDown().Bounds().Dy()-t.TailOverlap(), 0)
+	}
+	return 0
+}
+
+// rect positions the bubble within bounds
+func (sb *SpeechBubble) rect(th theme.Theme, bounds image.Rectangle) image.Rectangle {
+	size := image.Pt(min(sb.Size.X, bounds.Dx()), min(sb.Size.Y, bounds.Dy()))
+	gap := sb.gap(th)
+	r := image.Rectangle{Max: size}.Add(image.Pt(sb.Anchor.X-size.X/2, sb.Anchor.Y-gap-size.Y))
+	if r.Min.Y < bounds.Min.Y {
+		r = r.Add(image.Pt(0, sb.Anchor.Y+gap-r.Min.Y))
+	}
+	switch {
+	case r.Min.X < bounds.Min.X:
+		r = r.Add(image.Pt(bounds.Min.X-r.Min.X, 0))
+	case r.Max.X > bounds.Max.X:
+		r = r.Sub(image.Pt(r.Max.X-bounds.Max.X, 0))
+	}
+	switch {
+	case r.Min.Y < bounds.Min.Y:
+		r = r.Add(image.Pt(0, bounds.Min.Y-r.Min.Y))
+	case r.Max.Y > bounds.Max.Y:
+		r = r.Sub(image.Pt(0, r.Max.Y-bounds.Max.Y))
+	}
+	return r
+}
+
+// tail returns the tail image and where it goes for a bubble framed by frameRect, or nil if there is no tail
+func (sb *SpeechBubble) tail(th theme.Theme, frameRect image.Rectangle) (image.Image, image.Rectangle) {
+	t, ok := tailOf(th)
+	if !ok || sb.Anchor.In(frameRect) {
+		return nil, image.Rectangle{}
+	}
+	up := frameRect.Min.Y >= sb.Anchor.Y
+	ti := t.TailDown()
+	if up {
+		ti = t.TailUp()
+	}
+	size := ti.Bounds().Size()
+	minX, maxX := frameRect.Min.X, frameRect.Max.X-size.X
+	if f, ok := th.(theme.Frame); ok {
+		fc := f.FrameCenter()
+		minX += fc.Min.X - f.Frame().Bounds().Min.X
+		maxX -= f.Frame().Bounds().Max.X - fc.Max.X
+	}
+	x := sb.Anchor.X - size.X/2
+	if minX <= maxX {
+		x = min(max(x, minX), maxX)
+	}
+	y := frameRect.Max.Y - t.TailOverlap()
+	if up {
+		y = frameRect.Min.Y + t.TailOverlap() - size.Y
+	}
+	return ti, image.Rectangle{Max: size}.Add(image.Pt(x, y))
+}
+
+// drawTail draws the speech bubble's tail
+func (tb *TextBox) drawTail(target wordwrap.Image, layout Layout, options ...wordwrap.DrawOption) {
+	if tb.speechBubble == nil {
+		return
+	}
+	tl, ok := layout.(TailLayout)
+	if !ok {
+		return
+	}
+	tr := tl.TailRect()
+	ti, _ := tb.speechBubble.tail(tb.theme, layout.FrameRect())
+	if ti == nil || tr.Empty() {
+		return
+	}
+	for _, option := range options {
+		switch option := option.(type) {
+		case wordwrap.SourceImageMapper:
+			ti = option(ti)
+		}
+	}
+	draw.Draw(target.SubImage(tr).(wordwrap.Image), tr, ti, ti.Bounds().Min, draw.Over)
+}
@@ -0,0 +1,132 @@
+package rpgtextbox
+
+import (
+	"image"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+)
+
+func TestSpeechBubble(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	bounds := image.Rect(0, 0, 400, 300)
+	size := image.Pt(220, 100)
+	tests := []struct {
+		name   string
+		anchor image.Point
+		below  bool
+	}{
+		{"Above", image.Pt(200, 200), false},
+		{"BelowWhenNoRoomAbove", image.Pt(200, 30), true},
+		{"KeptInsideLeft", image.Pt(10, 200), false},
+		{"KeptInsideRight", image.Pt(390, 200), false},
+	}
+	for _, tt := range tests {
+		tb, err := NewSimpleTextBox(theme, "Hello there", bounds.Size(), NewSpeechBubble(tt.anchor, size))
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", tt.name, err)
+		}
+		l, err := NewSimpleLayout(tb, bounds)
+		if err != nil {
+			t.Fatalf("%s: Error creating layout: %v", tt.name, err)
+		}
+		if l.FrameRect().Size() != size {
+			t.Errorf("%s: frame size %v want %v", tt.name, l.FrameRect().Size(), size)
+		}
+		if !l.FrameRect().In(bounds) {
+			t.Errorf("%s: frame %v outside of %v", tt.name, l.FrameRect(), bounds)
+		}
+		tail := l.TailRect()
+		if tail.Empty() {
+			t.Fatalf("%s: expected a tail", tt.name)
+		}
+		tip := tail.Max.Y
+		if tt.below {
+			tip = tail.Min.Y
+		}
+		if tip != tt.anchor.Y {
+			t.Errorf("%s: tail tip at y %d want %d", tt.name, tip, tt.anchor.Y)
+		}
+		if below := l.FrameRect().Min.Y >= tt.anchor.Y; below != tt.below {
+			t.Errorf("%s: frame %v below anchor %v = %v want %v", tt.name, l.FrameRect(), tt.anchor, below, tt.below)
+		}
+		if tail.Min.X < l.FrameRect().Min.X || tail.Max.X > l.FrameRect().Max.X {
+			t.Errorf("%s: tail %v not attached to frame %v", tt.name, tail, l.FrameRect())
+		}
+	}
+}
+
+func TestSpeechBubbleWrapped(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	bounds := image.Rect(0, 0, 400, 300)
+	bubble := NewSpeechBubble(image.Pt(200, 200), image.Pt(220, 100))
+	for name, wt := range wrappedThemes(t, th) {
+		tb, err := NewSimpleTextBox(wt, "Hello there", bounds.Size(), bubble)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		l, err := NewSimpleLayout(tb, bounds)
+		if err != nil {
+			t.Fatalf("%s: Error creating layout: %v", name, err)
+		}
+		if l.TailRect().Empty() {
+			t.Errorf("%s: expected a tail", name)
+		}
+	}
+	for name, wt := range wrappedThemes(t, &spacedTheme{Theme: th, frame: th}) {
+		tb, err := NewSimpleTextBox(wt, "Hello there", bounds.Size(), bubble)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		if _, err := tb.DrawNextPageFrame(image.NewRGBA(bounds)); err != nil {
+			t.Errorf("%s: Draw next frame error: %v", name, err)
+		}
+		l, err := NewSimpleLayout(tb, bounds)
+		if err != nil {
+			t.Fatalf("%s: Error creating layout: %v", name, err)
+		}
+		if !l.TailRect().Empty() {
+			t.Errorf("%s: expected no tail without one in the theme", name)
+		}
+	}
+}
+
+func TestSpeechBubbleBaseLayout(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	bounds := image.Rect(0, 0, 400, 300)
+	var tail, frame image.Rectangle
+	tb, err := NewSimpleTextBox(theme, "Hello there", bounds.Size(), NewSpeechBubble(image.Pt(200, 200), image.Pt(220, 100)), LayoutFactory(func(tb *TextBox, destRect image.Rectangle) (Layout, error) {
+		sl, err := NewSimpleLayout(tb, destRect)
+		if err != nil {
+			return nil, err
+		}
+		tail, frame = sl.TailRect(), sl.FrameRect()
+		return &baseLayout{sl}, nil
+	}))
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(bounds)
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if tail.Empty() {
+		t.Fatalf("Expected the simple layout to have a tail")
+	}
+	for y := tail.Min.Y; y < tail.Max.Y; y++ {
+		for x := tail.Min.X; x < tail.Max.X; x++ {
+			if !image.Pt(x, y).In(frame) && i.RGBAAt(x, y).A != 0 {
+				t.Fatalf("Expected no tail for a layout without TailRect, drawn at %d, %d", x, y)
+			}
+		}
+	}
+}
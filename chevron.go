@@ -0,0 +1,127 @@
+package rpgtextbox
+
+import (
+	"image"
+	"time"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	wordwrap "github.com/arran4/golang-wordwrap"
+)
+
+// chevronWait is the state of the more chevron's animation while waiting for user input
+type chevronWait struct {
+	// frame is the index of the chevron frame drawn last
+	frame int
+	// lastPage is what DrawNextFrame returned when the wait started
+	lastPage bool
+}
+
+// shownLines is what drawLines drew last, so that it can be drawn again with the next chevron frame
+type shownLines struct {
+	layout  Layout
+	ls      []wordwrap.Line
+	scroll  int
+	chevron bool
+	more    bool
+}
+
+// chevronFrames returns the theme's chevron animation if it has one
+func (tb *TextBox) chevronFrames() []theme.ChevronFrame {
+	if ac, ok := tb.theme.(theme.AnimatedChevron); ok {
+		return ac.ChevronFrames()
+	}
+	return nil
+}
+
+// chevronImage returns the chevron to draw, the current frame if the chevron is animated
+func (tb *TextBox) chevronImage() image.Image {
+	frames := tb.chevronFrames()
+	if len(frames) == 0 {
+		return tb.theme.Chevron()
+	}
+	if tb.chevronWait != nil {
+		return frames[tb.chevronWait.frame%len(frames)].Image
+	}
+	return frames[0].Image
+}
+
+// chevronOptions replaces the TextEndChevron with the current frame of an animated chevron
+func (tb *TextBox) chevronOptions(opts []wordwrap.DrawOption) []wordwrap.DrawOption {
+	if tb.moreChevronLocation != TextEndChevron || len(tb.chevronFrames()) == 0 {
+		return opts
+	}
+	ci := tb.chevronImage()
+	config := wordwrap.NewDrawConfig(opts...)
+	return append(opts, wordwrap.BoxDrawMap(func(box wordwrap.Box, dc *wordwrap.DrawConfig, bps *wordwrap.BoxPositionStats) wordwrap.Box {
+		if config.BoxDrawMap != nil {
+			if box = config.BoxDrawMap(box, dc, bps); box == nil {
+				return nil
+			}
+		}
+		if pb, ok := box.(*wordwrap.PageBreakBox); ok && pb.VisualBox != nil {
+			return wordwrap.NewPageBreak(wordwrap.NewImageBox(ci, wordwrap.ImageBoxMetricCenter(tb.theme.FontDrawer())))
+		}
+		return box
+	}))
+}
+
+// startChevronAnimation starts animating the chevron if the page just drawn shows one and the theme animates it.
+// Returns how long to show the first frame for.
+func (tb *TextBox) startChevronAnimation(lastPage bool) (time.Duration, bool) {
+	frames := tb.chevronFrames()
+	if len(frames) == 0 || tb.moreChevronLocation == NoMoreChevron || tb.shown == nil || !tb.shown.chevron {
+		return 0, false
+	}
+	tb.chevronWait = &chevronWait{
+		lastPage: lastPage,
+	}
+	return frames[0].Duration, true
+}
+
+// drawChevronFrame draws the lines last drawn again with the next frame of the chevron
+func (tb *TextBox) drawChevronFrame(target wordwrap.Image) (lastPage bool, userInputAccepted bool, wait time.Duration, err error) {
+	frames := tb.chevronFrames()
+	if len(frames) == 0 {
+		tb.chevronWait = nil
+		return tb.DrawNextFrame(target)
+	}
+	if tb.shown == nil {
+		// Restored from a State mid-wait, nothing has been drawn yet
+		if tb.shown, err = tb.waitingLines(target.Bounds()); err != nil {
+			return
+		}
+	}
+	tb.chevronWait.frame = (tb.chevronWait.frame + 1) % len(frames)
+	s := tb.shown
+	if _, err = tb.drawLines(target, s.layout, s.ls, s.scroll, s.chevron, s.more); err != nil {
+		return
+	}
+	return tb.chevronWait.lastPage, true, frames[tb.chevronWait.frame].Duration, nil
+}
+
+// waitingLines works out the lines shown while waiting for user input at the end of the current page, or the current
+// line for the ScrollAnimation
+func (tb *TextBox) waitingLines(bounds image.Rectangle) (*shownLines, error) {
+	layout, err := tb.newLayout(bounds)
+	if err != nil {
+		return nil, err
+	}
+	ls := tb.pages[tb.nextPage-1].ls
+	more := tb.HasNext()
+	if sa, ok := tb.animation.(*ScrollAnimation); ok && sa.page != nil {
+		ls = tb.linesBefore(tb.nextPage-1, sa.lineNumber, layout.TextRect().Dy())
+		more = more || sa.lineNumber < len(sa.page.ls)
+	}
+	return &shownLines{
+		layout:  layout,
+		ls:      ls,
+		chevron: true,
+		more:    more,
+	}, nil
+}
+
+// Continue tells the text box the player has responded while the chevron is animating, so the next DrawNextFrame moves
+// on instead of drawing another chevron frame. Does nothing if the chevron isn't animating.
+func (tb *TextBox) Continue() {
+	tb.chevronWait = nil
+}
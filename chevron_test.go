@@ -0,0 +1,216 @@
+package rpgtextbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+	"time"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+)
+
+// animatedChevronTheme adds chevron frames to a theme
+type animatedChevronTheme struct {
+	*spacedTheme
+	frames []theme.ChevronFrame
+}
+
+func (at *animatedChevronTheme) ChevronFrames() []theme.ChevronFrame {
+	return at.frames
+}
+
+func TestAnimatedChevron(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	// A sprite sheet of the chevron next to a solid red one
+	size := th.Chevron().Bounds().Size()
+	sheet := image.NewRGBA(image.Rect(0, 0, size.X*2, size.Y))
+	draw.Draw(sheet, image.Rectangle{Max: size}, th.Chevron(), th.Chevron().Bounds().Min, draw.Src)
+	draw.Draw(sheet, image.Rectangle{Max: size}.Add(image.Pt(size.X, 0)), image.NewUniform(color.RGBA{R: 255, A: 255}), image.Point{}, draw.Src)
+	frames := theme.ChevronFramesFromSheet(sheet, size, time.Second/4)
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames from the sheet got %d", len(frames))
+	}
+	frames[1].Duration = time.Second / 8
+	at := &animatedChevronTheme{&spacedTheme{Theme: th, frame: th}, frames}
+	boxSize := image.Pt(400, 150)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box. " +
+		"It goes on and on for a while longer so that there is more than one page."
+	for _, location := range []MoreChevronLocations{CenterBottomInsideTextFrame, TextEndChevron} {
+		tb, err := NewSimpleTextBox(at, text, boxSize, location)
+		if err != nil {
+			t.Fatalf("%d: Error creating text box: %v", location, err)
+		}
+		var images [][]byte
+		for f := 0; f < 4; f++ {
+			i := image.NewRGBA(image.Rectangle{Max: boxSize})
+			_, ui, wait, err := tb.DrawNextFrame(i)
+			if err != nil {
+				t.Fatalf("%d: Draw next frame error: %v", location, err)
+			}
+			if want := frames[f%2].Duration; !ui || wait != want {
+				t.Errorf("%d: frame %d accepted user input %v waiting %v want true %v", location, f, ui, wait, want)
+			}
+			images = append(images, i.Pix)
+		}
+		if tb.CurrentPage() != 0 {
+			t.Errorf("%d: expected to stay on the first page while the chevron animates, on %d", location, tb.CurrentPage())
+		}
+		if bytes.Equal(images[0], images[1]) || !bytes.Equal(images[0], images[2]) || !bytes.Equal(images[1], images[3]) {
+			t.Errorf("%d: expected the chevron to alternate between its frames", location)
+		}
+		tb.Continue()
+		if _, _, _, err := tb.DrawNextFrame(image.NewRGBA(image.Rectangle{Max: boxSize})); err != nil {
+			t.Fatalf("%d: Draw next frame error: %v", location, err)
+		}
+		if tb.CurrentPage() != 1 {
+			t.Errorf("%d: expected Continue to move on to the second page, on %d", location, tb.CurrentPage())
+		}
+	}
+}
+
+func TestAnimatedChevronLastPage(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	frames := []theme.ChevronFrame{{Image: th.Chevron(), Duration: time.Second}}
+	at := &animatedChevronTheme{&spacedTheme{Theme: th, frame: th}, frames}
+	tb, err := NewSimpleTextBox(at, "Short", image.Pt(400, 150), CenterBottomInsideTextFrame, NewBoxByBoxAnimation())
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	for f := 0; ; f++ {
+		if f > 100 {
+			t.Fatalf("Animation never finished")
+		}
+		lastPage, ui, wait, err := tb.DrawNextFrame(image.NewRGBA(image.Rect(0, 0, 400, 150)))
+		if err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+		if ui && wait > 0 {
+			t.Fatalf("Expected no chevron animation on the last page")
+		}
+		if lastPage && !ui && wait < 0 {
+			break
+		}
+	}
+}
+
+func TestAnimatedChevronWrapped(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	frames := []theme.ChevronFrame{{Image: th.Chevron(), Duration: time.Second / 4}, {Image: th.Chevron(), Duration: time.Second / 8}}
+	at := &animatedChevronTheme{&spacedTheme{Theme: th, frame: th}, frames}
+	boxSize := image.Pt(400, 150)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box. " +
+		"It goes on and on for a while longer so that there is more than one page."
+	for name, wt := range wrappedThemes(t, at) {
+		tb, err := NewSimpleTextBox(wt, text, boxSize, CenterBottomInsideTextFrame)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		for f := 0; f < 2; f++ {
+			_, _, wait, err := tb.DrawNextFrame(image.NewRGBA(image.Rectangle{Max: boxSize}))
+			if err != nil {
+				t.Fatalf("%s: Draw next frame error: %v", name, err)
+			}
+			if want := frames[f].Duration; wait != want {
+				t.Errorf("%s: frame %d waiting %v want %v", name, f, wait, want)
+			}
+		}
+	}
+	for name, wt := range wrappedThemes(t, th) {
+		if frames := (&TextBox{theme: wt}).chevronFrames(); len(frames) != 0 {
+			t.Errorf("%s: Expected no chevron frames got %d", name, len(frames))
+		}
+	}
+}
+
+func TestAnimatedChevronRestore(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	red := image.NewUniform(color.RGBA{R: 255, A: 255})
+	frames := []theme.ChevronFrame{
+		{Image: th.Chevron(), Duration: time.Second / 4},
+		{Image: image.NewRGBA(th.Chevron().Bounds()), Duration: time.Second / 8},
+		{Image: th.Chevron(), Duration: time.Second / 2},
+	}
+	draw.Draw(frames[1].Image.(*image.RGBA), frames[1].Image.Bounds(), red, image.Point{}, draw.Src)
+	at := &animatedChevronTheme{&spacedTheme{Theme: th, frame: th}, frames}
+	boxSize := image.Pt(400, 150)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box. " +
+		"It goes on and on for a while longer so that there is more than one page."
+	tests := []struct {
+		name      string
+		animation func() Option
+		frames    int
+	}{
+		{"NoAnimation", func() Option { return CenterBottomInsideTextFrame }, 2},
+		{"ScrollAnimation", func() Option { return NewScrollAnimation() }, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, err := NewSimpleTextBox(at, text, boxSize, CenterBottomInsideTextFrame, tt.animation())
+			if err != nil {
+				t.Fatalf("Error creating text box: %v", err)
+			}
+			for f := 0; f < tt.frames; f++ {
+				if _, _, _, err := original.DrawNextFrame(image.NewRGBA(image.Rectangle{Max: boxSize})); err != nil {
+					t.Fatalf("Draw next frame error: %v", err)
+				}
+			}
+			state := original.Snapshot()
+			if state.ChevronWait == nil || state.ChevronWait.Frame == 0 {
+				t.Fatalf("Expected to snapshot part way through the chevron animation got %+v", state.ChevronWait)
+			}
+			b, err := json.Marshal(state)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			state = &State{}
+			if err := json.Unmarshal(b, state); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			restored, err := NewSimpleTextBox(at, text, boxSize, CenterBottomInsideTextFrame, tt.animation())
+			if err != nil {
+				t.Fatalf("Error creating text box: %v", err)
+			}
+			if err := restored.Restore(state); err != nil {
+				t.Fatalf("Restore error: %v", err)
+			}
+			for f := 0; f < 4; f++ {
+				if f == 2 {
+					original.Continue()
+					restored.Continue()
+				}
+				want := image.NewRGBA(image.Rectangle{Max: boxSize})
+				got := image.NewRGBA(image.Rectangle{Max: boxSize})
+				wl, wui, ww, err := original.DrawNextFrame(want)
+				if err != nil {
+					t.Fatalf("Draw next frame error: %v", err)
+				}
+				gl, gui, gw, err := restored.DrawNextFrame(got)
+				if err != nil {
+					t.Fatalf("Draw next frame error: %v", err)
+				}
+				if wl != gl || wui != gui || ww != gw {
+					t.Errorf("frame %d: got (%v, %v, %v) want (%v, %v, %v)", f, gl, gui, gw, wl, wui, ww)
+				}
+				if !bytes.Equal(want.Pix, got.Pix) {
+					t.Errorf("frame %d: restored text box rendered differently", f)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package rpgtextbox
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/arran4/golang-rpg-textbox/util"
+	wordwrap "github.com/arran4/golang-wordwrap"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+)
+
+// ChoiceMenu is an Option which adds a list of selectable choices to the bottom of the text area. Space for the menu is
+// reserved on every page but it is only drawn on the last page. Use Select, Next and Previous to move the cursor, and
+// Choose to get the result.
+type ChoiceMenu struct {
+	choices  []string
+	selected int
+	// Cursor is drawn next to the selected choice, if nil the theme's chevron is used. It is scaled down to the height
+	// of a line of text if it is taller
+	Cursor image.Image
+	// rects are where each choice was last drawn
+	rects []image.Rectangle
+}
+
+// ChoiceLayout is an optional extension of Layout for layouts with an area for the ChoiceMenu, without it the menu
+// isn't drawn
+type ChoiceLayout interface {
+	// ChoiceRect is the optional area containing the choice menu.
+	ChoiceRect() image.Rectangle
+}
+
+// NewChoiceMenu creates a ChoiceMenu with the first choice selected
+func NewChoiceMenu(choices ...string) *ChoiceMenu {
+	return &ChoiceMenu{
+		choices: choices,
+	}
+}
+
+// apply Set the menu when used as an Option
+func (cm *ChoiceMenu) apply(box *TextBox) {
+	box.choiceMenu = cm
+}
+
+// ChoiceMenu returns the choice menu the text box was created with, or nil
+func (tb *TextBox) ChoiceMenu() *ChoiceMenu {
+	return tb.choiceMenu
+}
+
+// Choices returns the choices in the menu
+func (cm *ChoiceMenu) Choices() []string {
+	return cm.choices
+}
+
+// Selected returns the index of the choice the cursor is on
+func (cm *ChoiceMenu) Selected() int {
+	return cm.selected
+}
+
+// Select moves the cursor to the choice at index i
+func (cm *ChoiceMenu) Select(i int) error {
+	if i < 0 || i >= len(cm.choices) {
+		return fmt.Errorf("choice %d out of range, %d choices", i, len(cm.choices))
+	}
+	cm.selected = i
+	return nil
+}
+
+// Next moves the cursor down one choice, wrapping around to the first
+func (cm *ChoiceMenu) Next() {
+	if len(cm.choices) > 0 {
+		cm.selected = (cm.selected + 1) % len(cm.choices)
+	}
+}
+
+// Previous moves the cursor up one choice, wrapping around to the last
+func (cm *ChoiceMenu) Previous() {
+	if len(cm.choices) > 0 {
+		cm.selected = (cm.selected + len(cm.choices) - 1) % len(cm.choices)
+	}
+}
+
+// Choose returns the index and text of the selected choice
+func (cm *ChoiceMenu) Choose() (int, string) {
+	if cm.selected >= len(cm.choices) {
+		return -1, ""
+	}
+	return cm.selected, cm.choices[cm.selected]
+}
+
+// ChoiceAt returns the index of the choice drawn at p in the most recent frame, useful for mouse input if you are not
+// using a SpaceMap
+func (cm *ChoiceMenu) ChoiceAt(p image.Point) (int, bool) {
+	for i, r := range cm.rects {
+		if p.In(r) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// cursor returns the cursor image
+func (cm *ChoiceMenu) cursor(tb *TextBox) image.Image {
+	if cm.Cursor != nil {
+		return cm.Cursor
+	}
+	return tb.theme.Chevron()
+}
+
+// rowHeight returns the height of a single choice, which is the height of a line of text
+func (cm *ChoiceMenu) rowHeight(tb *TextBox) int {
+	m := tb.theme.FontDrawer().Face.Metrics()
+	return util.Max((m.Ascent + m.Descent).Ceil(), 1)
+}
+
+// width returns the width of the widest choice including the cursor
+func (cm *ChoiceMenu) width(tb *TextBox) int {
+	cr := cm.cursor(tb).Bounds()
+	rowHeight := cm.rowHeight(tb)
+	width := 0
+	for _, choice := range cm.choices {
+		width = max(width, font.MeasureString(tb.theme.FontDrawer().Face, choice).Ceil())
+	}
+	if cr.Dy() > rowHeight {
+		return width + cr.Dx()*rowHeight/cr.Dy()
+	}
+	return width + cr.Dx()
+}
+
+// height returns the space the menu needs
+func (cm *ChoiceMenu) height(tb *TextBox) int {
+	return cm.rowHeight(tb) * len(cm.choices)
+}
+
+// ChoiceShape is the shape registered with the SpaceMap for each choice
+type ChoiceShape struct {
+	Index int
+	Text  string
+	Rect  image.Rectangle
+}
+
+func (c *ChoiceShape) Bounds() image.Rectangle {
+	return c.Rect
+}
+
+func (c *ChoiceShape) PointIn(x, y int) bool {
+	return image.Pt(x, y).In(c.Rect)
+}
+
+func (c *ChoiceShape) String() string {
+	return fmt.Sprintf("Choice(%d: %s)", c.Index, c.Text)
+}
+
+func (c *ChoiceShape) ID() interface{} {
+	return c.Index
+}
+
+// drawChoices draws the choice menu into the layout's choice rect
+func (tb *TextBox) drawChoices(target wordwrap.Image, layout Layout, options ...wordwrap.DrawOption) error {
+	cm := tb.choiceMenu
+	cm.rects = cm.rects[:0]
+	cl, ok := layout.(ChoiceLayout)
+	if !ok {
+		return nil
+	}
+	choiceRect := cl.ChoiceRect()
+	cursor := cm.cursor(tb)
+	for _, option := range options {
+		switch option := option.(type) {
+		case wordwrap.SourceImageMapper:
+			cursor = option(cursor)
+		}
+	}
+	config := wordwrap.NewDrawConfig(options...)
+	cr := cursor.Bounds()
+	rowHeight := cm.rowHeight(tb)
+	cursorSize := cr.Size()
+	if cursorSize.Y > rowHeight {
+		cursorSize = image.Pt(cursorSize.X*rowHeight/cursorSize.Y, rowHeight)
+	}
+	for i, choice := range cm.choices {
+		row := image.Rect(choiceRect.Min.X, choiceRect.Min.Y+i*rowHeight, choiceRect.Max.X, choiceRect.Min.Y+(i+1)*rowHeight)
+		cm.rects = append(cm.rects, row)
+		if i == cm.selected {
+			at := row.Min.Add(image.Pt(0, (rowHeight-cursorSize.Y)/2))
+			draw.ApproxBiLinear.Scale(target.SubImage(choiceRect).(wordwrap.Image), image.Rectangle{Min: at, Max: at.Add(cursorSize)}, cursor, cr, draw.Over, nil)
+		}
+		b, err := wordwrap.NewSimpleTextBox(tb.theme.FontDrawer(), choice)
+		if err != nil {
+			return err
+		}
+		m := b.MetricsRect()
+		textRect := row
+		textRect.Min.X += cursorSize.X
+		textRect.Min.Y += (rowHeight - (m.Ascent + m.Descent).Ceil()) / 2
+		b.DrawBox(target.SubImage(textRect.Intersect(choiceRect)).(wordwrap.Image), m.Ascent, config)
+		if tb.spaceMap != nil {
+			tb.spaceMap.Add(&ChoiceShape{
+				Index: i,
+				Text:  choice,
+				Rect:  row,
+			}, 1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package rpgtextbox
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	"github.com/arran4/spacemap/shared"
+)
+
+type shapeRecorder []shared.Shape
+
+func (sr *shapeRecorder) Add(shape shared.Shape, zIndex int) {
+	*sr = append(*sr, shape)
+}
+
+func TestChoiceMenu(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	textBoxSize := image.Pt(400, 200)
+	cm := NewChoiceMenu("Yes", "No", "Maybe")
+	tb, err := NewSimpleTextBox(theme, "Will you help us?", textBoxSize, cm)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	if err := cm.Select(3); err == nil {
+		t.Errorf("Expected error selecting a choice out of range")
+	}
+	cm.Previous()
+	if i, s := cm.Choose(); i != 2 || s != "Maybe" {
+		t.Errorf("Choose() = %d, %q after wrapping backwards, want 2, \"Maybe\"", i, s)
+	}
+	cm.Next()
+	cm.Next()
+	sr := &shapeRecorder{}
+	tb.SetSpaceMap(sr)
+	i := image.NewRGBA(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y))
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	var choices []*ChoiceShape
+	for _, s := range *sr {
+		if c, ok := s.(*ChoiceShape); ok {
+			choices = append(choices, c)
+		}
+	}
+	if len(choices) != 3 {
+		t.Fatalf("Expected 3 choice shapes got %d", len(choices))
+	}
+	for n, c := range choices {
+		if c.ID() != n || c.Text != cm.Choices()[n] {
+			t.Errorf("Choice shape %d is %v", n, c)
+		}
+		p := c.Rect.Min.Add(image.Pt(c.Rect.Dx()/2, c.Rect.Dy()/2))
+		if !c.PointIn(p.X, p.Y) {
+			t.Errorf("Expected %v to be in %v", p, c)
+		}
+		if found, ok := cm.ChoiceAt(p); !ok || found != n {
+			t.Errorf("ChoiceAt(%v) = %d, %v want %d", p, found, ok, n)
+		}
+		if !c.Rect.In(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y)) {
+			t.Errorf("Choice %d drawn outside the text box at %v", n, c.Rect)
+		}
+	}
+	if i, s := tb.ChoiceMenu().Choose(); i != 1 || s != "No" {
+		t.Errorf("Choose() = %d, %q want 1, \"No\"", i, s)
+	}
+}
+
+func TestChoiceMenuBaseLayout(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	textBoxSize := image.Pt(400, 200)
+	cm := NewChoiceMenu("Yes", "No")
+	tb, err := NewSimpleTextBox(theme, "Will you help us?", textBoxSize, cm, LayoutFactory(func(tb *TextBox, destRect image.Rectangle) (Layout, error) {
+		sl, err := NewSimpleLayout(tb, destRect)
+		if err != nil {
+			return nil, err
+		}
+		return &baseLayout{sl}, nil
+	}))
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	sr := &shapeRecorder{}
+	tb.SetSpaceMap(sr)
+	if _, err := tb.DrawNextPageFrame(image.NewRGBA(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y))); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	for _, s := range *sr {
+		if c, ok := s.(*ChoiceShape); ok {
+			t.Errorf("Expected no choices drawn for a layout without ChoiceRect got %v", c)
+		}
+	}
+	if _, ok := cm.ChoiceAt(image.Pt(textBoxSize.X/2, textBoxSize.Y/2)); ok {
+		t.Errorf("Expected no choice at the center of the text box")
+	}
+}
+
+func TestChoiceMenuTooTall(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	choices := make([]string, 20)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("Choice %d", i)
+	}
+	bounds := image.Rect(0, 0, 400, 150)
+	if _, err := NewSimpleTextBox(theme, "Pick one", bounds.Size(), NewChoiceMenu(choices...)); err == nil {
+		t.Errorf("Expected an error for a choice menu taller than the text area")
+	}
+}
+
+func TestChoiceMenuClipped(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	cm := NewChoiceMenu("Yes", "No")
+	cursor := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	draw.Draw(cursor, cursor.Bounds(), image.NewUniform(color.RGBA{R: 255, A: 255}), image.Point{}, draw.Src)
+	cm.Cursor = cursor
+	frameBounds := image.Rect(0, 0, 400, 150)
+	choiceRect := image.Rect(40, 200, 240, 205)
+	tb, err := NewSimpleTextBox(theme, "Will you help us?", frameBounds.Size(), cm, LayoutFactory(func(tb *TextBox, destRect image.Rectangle) (Layout, error) {
+		sl, err := NewSimpleLayout(tb, frameBounds)
+		if err != nil {
+			return nil, err
+		}
+		sl.choiceRect = choiceRect
+		return sl, nil
+	}))
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	drawn := false
+	for y := frameBounds.Max.Y; y < i.Rect.Max.Y; y++ {
+		for x := 0; x < i.Rect.Max.X; x++ {
+			if i.RGBAAt(x, y).A == 0 {
+				continue
+			}
+			if !image.Pt(x, y).In(choiceRect) {
+				t.Fatalf("Choice menu drawn outside %v at %d, %d", choiceRect, x, y)
+			}
+			drawn = true
+		}
+	}
+	if !drawn {
+		t.Errorf("Expected the choice menu to be drawn in %v", choiceRect)
+	}
+}
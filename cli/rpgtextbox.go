@@ -6,7 +6,9 @@ import (
 	"image/color/palette"
 	"image/gif"
 	"log"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	pattern_cli "github.com/arran4/go-pattern/pkg/pattern-cli"
@@ -14,11 +16,13 @@ import (
 	"github.com/arran4/go-pattern/dsl"
 	"github.com/arran4/golang-frame/frames"
 	rpgtextbox "github.com/arran4/golang-rpg-textbox"
+	"github.com/arran4/golang-rpg-textbox/dialogue"
 	"github.com/arran4/golang-rpg-textbox/theme"
 	"github.com/arran4/golang-rpg-textbox/theme/cache"
 	"github.com/arran4/golang-rpg-textbox/theme/dynamic"
 	"github.com/arran4/golang-rpg-textbox/theme/fromdirpng"
 	"github.com/arran4/golang-rpg-textbox/util"
+	wordwrap "github.com/arran4/golang-wordwrap"
 	"golang.org/x/image/draw"
 )
 
@@ -40,16 +44,40 @@ import (
 //	animation:   --animation   (default: "")          Use help for list
 //	frame:       --frame       (default: "")          Use help for list
 //	pattern:     --pattern     (default: "")          Use help for list
-//	fontColor:   --font-color  (default: "black")     Text font color (e.g., white, black)
-func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize string, textSource, outPrefix, chevronLoc, avatarPos, avatarScale, animation, frame, pattern, fontColor string) error {
+//	fontColor:   --font-color  (default: "")          Text font color (e.g., white, black), default the theme's
+//	scriptSource: --script     (default: "")          Dialogue script to render as a conversation, not with --text, --markup or --min-size
+//	markup:      --markup      (default: false)       Parse {b}, {color=red}, {img=name} etc. markup in --text
+//	minSize:     --min-size    (default: "")          Shrink the font as far as this size so --text fits on one page
+func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize string, textSource, outPrefix, chevronLoc, avatarPos, avatarScale, animation, frame, pattern, fontColor, scriptSource string, markup bool, minSize string) error {
 
 	log.Printf("Starting")
 	textBoxSize := image.Pt(width, height)
 	var text string
+	var script *dialogue.Script
 	var err error
-	text, err = util.GetText(textSource)
-	if err != nil {
-		return fmt.Errorf("text fetch error: %w", err)
+	if scriptSource != "" {
+		var conflicts []string
+		if textSource != "" {
+			conflicts = append(conflicts, "--text")
+		}
+		if markup {
+			conflicts = append(conflicts, "--markup")
+		}
+		if minSize != "" {
+			conflicts = append(conflicts, "--min-size")
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("--script can not be used with %s", strings.Join(conflicts, ", "))
+		}
+		script, err = dialogue.ParseFile(scriptSource)
+		if err != nil {
+			return fmt.Errorf("script fetch error: %w", err)
+		}
+	} else {
+		text, err = util.GetText(textSource)
+		if err != nil {
+			return fmt.Errorf("text fetch error: %w", err)
+		}
 	}
 	gr, err := util.OpenFont(fontName)
 	if err != nil {
@@ -86,9 +114,12 @@ func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize
 		return nil
 	}
 
-	if frame != "" || pattern != "" || fontColor != "black" {
+	if frame != "" || pattern != "" || fontColor != "" {
 		t = dynamic.New(baseTheme, frame, pattern, fontColor)
 	}
+	if err := theme.Validate(t); err != nil {
+		return fmt.Errorf("theme error: %w", err)
+	}
 
 	var ops []rpgtextbox.Option
 	chevronLocs := map[string][]rpgtextbox.Option{
@@ -119,8 +150,12 @@ func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize
 		}
 	}
 	avatarPoss := map[string][]rpgtextbox.Option{
-		"left-avatar":  []rpgtextbox.Option{rpgtextbox.LeftAvatar},
-		"right-avatar": []rpgtextbox.Option{rpgtextbox.RightAvatar},
+		"left-avatar":            []rpgtextbox.Option{rpgtextbox.LeftAvatar},
+		"right-avatar":           []rpgtextbox.Option{rpgtextbox.RightAvatar},
+		"left-avatar-panel":      []rpgtextbox.Option{rpgtextbox.LeftAvatarPanel},
+		"right-avatar-panel":     []rpgtextbox.Option{rpgtextbox.RightAvatarPanel},
+		"top-left-avatar-panel":  []rpgtextbox.Option{rpgtextbox.TopLeftAvatarPanel},
+		"top-right-avatar-panel": []rpgtextbox.Option{rpgtextbox.TopRightAvatarPanel},
 	}
 	if len(avatarPos) > 0 {
 		help := avatarPos == "help"
@@ -137,9 +172,15 @@ func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize
 		}
 	}
 	avatarScales := map[string][]rpgtextbox.Option{
-		"center-avatar":     []rpgtextbox.Option{rpgtextbox.CenterAvatar},
-		"nearest-neighbour": []rpgtextbox.Option{rpgtextbox.NearestNeighbour},
-		"approx-biLinear":   []rpgtextbox.Option{rpgtextbox.ApproxBiLinear},
+		"center-avatar":        []rpgtextbox.Option{rpgtextbox.CenterAvatar},
+		"nearest-neighbour":    []rpgtextbox.Option{rpgtextbox.NearestNeighbour},
+		"approx-biLinear":      []rpgtextbox.Option{rpgtextbox.ApproxBiLinear},
+		"catmull-rom":          []rpgtextbox.Option{rpgtextbox.CatmullRom},
+		"contain":              []rpgtextbox.Option{rpgtextbox.Contain},
+		"cover":                []rpgtextbox.Option{rpgtextbox.Cover},
+		"cover-top":            []rpgtextbox.Option{rpgtextbox.Cover, rpgtextbox.AvatarAnchorTop},
+		"integer-scale":        []rpgtextbox.Option{rpgtextbox.IntegerScale},
+		"integer-scale-bottom": []rpgtextbox.Option{rpgtextbox.IntegerScale, rpgtextbox.AvatarAnchorBottom},
 	}
 	if len(avatarScale) > 0 {
 		help := avatarScale == "help"
@@ -159,11 +200,14 @@ func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize
 		"fade-animation":             []rpgtextbox.Option{rpgtextbox.NewFadeAnimation()},
 		"box-by-box-animation":       []rpgtextbox.Option{rpgtextbox.NewBoxByBoxAnimation()},
 		"letter-by-letter-animation": []rpgtextbox.Option{rpgtextbox.NewLetterByLetterAnimation()},
+		"scroll-animation":           []rpgtextbox.Option{rpgtextbox.NewScrollAnimation()},
 	}
 	if len(animation) > 0 {
 		help := animation == "help"
 		if os, ok := animations[animation]; ok {
-			ops = append(ops, os...)
+			if script == nil {
+				ops = append(ops, os...)
+			}
 			ext = "gif"
 			animated = true
 		} else {
@@ -177,20 +221,82 @@ func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize
 		}
 	}
 
-	tb, err := rpgtextbox.NewSimpleTextBox(t, text, textBoxSize, ops...)
-	if err != nil {
-		return fmt.Errorf("error %w", err)
+	var tb interface {
+		DrawNextFrame(target wordwrap.Image) (bool, bool, time.Duration, error)
+		DrawNextPageFrame(target wordwrap.Image, opts ...wordwrap.DrawOption) (bool, error)
+		Continue()
 	}
-
-	pages, err := tb.CalculateAllPages(textBoxSize)
-	if err != nil {
-		return fmt.Errorf("text fetch error: %w", err)
+	var pages int
+	if script != nil {
+		for _, l := range script.Lines {
+			if l.Animation == "" && animated {
+				l.Animation = animation
+			}
+			if l.Animation != "" && l.Animation != "no-animation" {
+				ext = "gif"
+				animated = true
+			}
+		}
+		c, err := dialogue.NewConversation(t, script, textBoxSize, ops...)
+		if err != nil {
+			return fmt.Errorf("error %w", err)
+		}
+		for i := 0; i < c.Len(); i++ {
+			n, err := c.TextBox(i).CalculateAllPages(textBoxSize)
+			if err != nil {
+				return fmt.Errorf("text fetch error: %w", err)
+			}
+			pages += n
+		}
+		tb = c
+	} else {
+		args := []interface{}{text}
+		if markup {
+			m, err := rpgtextbox.NewMarkupForTheme(t)
+			if err != nil {
+				return fmt.Errorf("markup fonts error: %w", err)
+			}
+			m.Regular = gr
+			if fs, ok := t.(theme.FontSized); !ok || fs.FontSize() <= 0 {
+				// The theme's font is the one made from the flags
+				m.Size = fFontSize
+				m.DPI = fDpi
+			}
+			m.ImageDir = "."
+			if textSource != "-" {
+				m.ImageDir = filepath.Dir(textSource)
+			}
+			if args, err = m.Parse(text); err != nil {
+				return fmt.Errorf("markup error: %w", err)
+			}
+		}
+		args = append(args, textBoxSize)
+		if minSize != "" {
+			fMinSize, err := strconv.ParseFloat(minSize, 64)
+			if err != nil {
+				return fmt.Errorf("invalid float value for min size: %s", minSize)
+			}
+			args = append(args, rpgtextbox.NewShrinkToFit(gr, fFontSize, fMinSize, fDpi))
+		}
+		for _, o := range ops {
+			args = append(args, o)
+		}
+		stb, err := rpgtextbox.NewRichTextBox(t, args...)
+		if err != nil {
+			return fmt.Errorf("error %w", err)
+		}
+		pages, err = stb.CalculateAllPages(textBoxSize)
+		if err != nil {
+			return fmt.Errorf("text fetch error: %w", err)
+		}
+		tb = stb
 	}
 
 	if animated {
 		gifo := &gif.GIF{}
 		f := 0
 		page := 0
+		var waited time.Duration
 		ofn := fmt.Sprintf("%s-animated.%s", outPrefix, ext)
 		for {
 			i := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -212,6 +318,13 @@ func GenerateTextBox(width, height int, themeDir, fontName string, dpi, fontSize
 				draw.Draw(palettedImage, palettedImage.Rect, i, bounds.Min, draw.Over)
 				gifo.Image = append(gifo.Image, palettedImage)
 				gifo.Delay = append(gifo.Delay, int(w/(time.Second/100)))
+				if ui {
+					// Show an animated chevron for about as long as a still page before moving on
+					if waited += w; waited >= time.Second/2 {
+						tb.Continue()
+						waited = 0
+					}
+				}
 			}
 		}
 		log.Printf("Saving %s", ofn)
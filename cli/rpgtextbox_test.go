@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTextBoxScriptConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		textSource string
+		markup     bool
+		minSize    string
+		want       string
+	}{
+		{"Text", "sample1.txt", false, "", "--text"},
+		{"Markup", "", true, "", "--markup"},
+		{"MinSize", "", false, "8", "--min-size"},
+		{"All", "sample1.txt", true, "8", "--text, --markup, --min-size"},
+	}
+	for _, tt := range tests {
+		err := GenerateTextBox(400, 150, "", "goregular", "75", "16", tt.textSource, t.TempDir()+"/out", "", "", "", "", "", "", "", "script.txt", tt.markup, tt.minSize)
+		if err == nil || !strings.HasSuffix(err.Error(), tt.want) {
+			t.Errorf("%s: GenerateTextBox() error = %v want one ending %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -53,6 +53,7 @@ func (tb *SampleTextBox) RenderAnimation(width, height int, outdir string) {
 	gifo := &gif.GIF{}
 	f := 0
 	page := 0
+	var waited time.Duration
 	for {
 		i := image.NewRGBA(image.Rect(0, 0, width, height))
 		if done, ui, w, err := tb.rtb.DrawNextFrame(i); err != nil {
@@ -73,6 +74,13 @@ func (tb *SampleTextBox) RenderAnimation(width, height int, outdir string) {
 			draw.Draw(palettedImage, palettedImage.Rect, i, bounds.Min, draw.Over)
 			gifo.Image = append(gifo.Image, palettedImage)
 			gifo.Delay = append(gifo.Delay, int(w/(time.Second/100)))
+			if ui {
+				// Show an animated chevron for about as long as a still page before moving on
+				if waited += w; waited >= time.Second/2 {
+					tb.rtb.Continue()
+					waited = 0
+				}
+			}
 		}
 	}
 	ofn := filepath.Join(outdir, tb.Filename)
@@ -186,6 +194,10 @@ func GenerateAnimationSamples(width, height int, textSource, outDir string) erro
 			Options:     []rpgtextbox.Option{rpgtextbox.NewLetterByLetterAnimation()},
 			Description: "letter-by-letter-animation",
 		},
+		{
+			Options:     []rpgtextbox.Option{rpgtextbox.NewScrollAnimation()},
+			Description: "scroll-animation",
+		},
 	}
 	OptionDescriptionBuild(func(oas []string, oa []rpgtextbox.Option) {
 		addTextBox(strings.Join(oas, "+")+".gif", Must(rpgtextbox.NewSimpleTextBox(t, text, textBoxSize, oa...)))
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+
+	pattern_cli "github.com/arran4/go-pattern/pkg/pattern-cli"
+
+	"github.com/arran4/go-pattern/dsl"
+	"github.com/arran4/golang-frame/frames"
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/theme/dynamic"
+	"github.com/arran4/golang-rpg-textbox/theme/fromdirpng"
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	"golang.org/x/image/draw"
+)
+
+// ValidateReport is the output of `rpgtextbox theme validate --format json`
+type ValidateReport struct {
+	Theme    string               `json:"theme"`
+	Valid    bool                 `json:"valid"`
+	Problems []fromdirpng.Problem `json:"problems"`
+}
+
+// ThemeValidate is a subcommand `rpgtextbox theme validate`
+//
+// Flags:
+//
+//	themeDir: --themedir (default: "./theme") Directory of the theme to check
+//	format:   --format   (default: "text")    Format of the output, text or json
+//
+// Exits non-zero if there are any problems other than warnings.
+func ThemeValidate(themeDir string, format string) error {
+	problems := fromdirpng.Check(themeDir)
+	errs := fromdirpng.Errors(problems)
+	switch format {
+	case "json":
+		report := ValidateReport{
+			Theme:    themeDir,
+			Valid:    len(errs) == 0,
+			Problems: problems,
+		}
+		if report.Problems == nil {
+			report.Problems = []fromdirpng.Problem{}
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "text":
+		for _, p := range problems {
+			fmt.Println(p.Error())
+		}
+		if len(errs) == 0 {
+			fmt.Printf("%s: ok\n", themeDir)
+		}
+	default:
+		return fmt.Errorf("unknown format %q, expected text or json", format)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d problems found in %s", len(errs), themeDir)
+	}
+	return nil
+}
+
+// ThemeInit is a subcommand `rpgtextbox theme init`
+//
+// Flags:
+//
+//	themeDir: --themedir (default: "./theme") Directory to create the theme in
+//	frame:    --frame    (default: "")        Use help for list
+//	pattern:  --pattern  (default: "")        Use help for list
+//	force:    --force    (default: false)     Overwrite an existing theme
+//
+// Writes the simple theme's images and a theme.yaml manifest. With --frame or --pattern the frame is rendered from the
+// named frame and pattern instead, with the chevron shrunk to fit in the frame's bottom border if needed.
+func ThemeInit(themeDir string, frame string, pattern string, force bool) error {
+	if frame == "help" {
+		for k := range frames.ByName {
+			log.Printf("%s", k)
+		}
+		return nil
+	}
+	if pattern == "help" {
+		fm := make(dsl.FuncMap)
+		pattern_cli.RegisterGeneratedCommands(fm)
+		for k := range fm {
+			log.Printf("%s", k)
+		}
+		return nil
+	}
+
+	m := fromdirpng.DefaultManifest
+	files := map[string][]byte{
+		m.Chevron: simple.ChevronBytes,
+		m.Frame:   simple.FrameBytes,
+		m.Avatar:  simple.AvatarBytes,
+	}
+	if frame != "" || pattern != "" {
+		base, err := simple.New()
+		if err != nil {
+			return fmt.Errorf("theme fetch error: %w", err)
+		}
+		t := dynamic.New(base, frame, pattern, "")
+		if err := theme.Validate(t); err != nil {
+			return fmt.Errorf("theme error: %w", err)
+		}
+		fImg := t.Frame()
+		buf := bytes.NewBuffer(nil)
+		if err := png.Encode(buf, fImg); err != nil {
+			return fmt.Errorf("encoding frame: %w", err)
+		}
+		files[m.Frame] = buf.Bytes()
+		c := t.FrameCenter().Sub(fImg.Bounds().Min)
+		m.FrameCenter = []int{c.Min.X, c.Min.Y, c.Max.X, c.Max.Y}
+		// Shrink the chevron to fit the frame's bottom border so the OnFrame chevron locations don't push the text up
+		if border, cb := fImg.Bounds().Dy()-c.Max.Y, base.Chevron().Bounds(); cb.Dy() > border {
+			if border <= 0 {
+				return fmt.Errorf("frame %s has no bottom border for the chevron", frame)
+			}
+			ci := image.NewNRGBA(image.Rect(0, 0, max(cb.Dx()*border/cb.Dy(), 1), border))
+			draw.CatmullRom.Scale(ci, ci.Bounds(), base.Chevron(), cb, draw.Src, nil)
+			buf := bytes.NewBuffer(nil)
+			if err := png.Encode(buf, ci); err != nil {
+				return fmt.Errorf("encoding chevron: %w", err)
+			}
+			files[m.Chevron] = buf.Bytes()
+		}
+	}
+
+	if !force {
+		names := append([]string{}, fromdirpng.ManifestFiles...)
+		for name := range files {
+			names = append(names, name)
+		}
+		for _, name := range names {
+			if _, err := os.Stat(filepath.Join(themeDir, name)); err == nil {
+				return fmt.Errorf("%s already exists, use --force to overwrite", filepath.Join(themeDir, name))
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	if err := os.MkdirAll(themeDir, 0755); err != nil {
+		return fmt.Errorf("creating theme directory: %w", err)
+	}
+	for _, name := range fromdirpng.ManifestFiles {
+		// Only reachable with --force, the written theme.yaml would otherwise lose to an earlier manifest file
+		if err := os.Remove(filepath.Join(themeDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	for name, b := range files {
+		if err := os.WriteFile(filepath.Join(themeDir, name), b, 0644); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	if err := fromdirpng.WriteManifest(themeDir, &m); err != nil {
+		return err
+	}
+	log.Printf("Created theme in %s", themeDir)
+	return nil
+}
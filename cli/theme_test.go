@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/arran4/golang-frame/frames"
+	"github.com/arran4/golang-rpg-textbox/theme/fromdirpng"
+)
+
+func TestThemeInit(t *testing.T) {
+	names := make([]string, 0, len(frames.ByName))
+	for name := range frames.ByName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	if len(names) == 0 {
+		t.Fatal("Expected golang-frame to have named frames")
+	}
+	for name, frame := range map[string]string{"Plain": "", "Frame": names[0]} {
+		dir := filepath.Join(t.TempDir(), "theme")
+		if err := ThemeInit(dir, frame, "", false); err != nil {
+			t.Fatalf("%s: ThemeInit: %v", name, err)
+		}
+		if problems := fromdirpng.Errors(fromdirpng.Check(dir)); len(problems) != 0 {
+			t.Errorf("%s: Expected the created theme to pass got %v", name, problems)
+		}
+		m, err := fromdirpng.ReadManifest(dir)
+		if err != nil {
+			t.Fatalf("%s: ReadManifest: %v", name, err)
+		}
+		th, err := fromdirpng.Load(dir, nil)
+		if err != nil {
+			t.Fatalf("%s: Load: %v", name, err)
+		}
+		if frame != "" {
+			def := frames.ByName[frame]
+			if got, want := th.Frame().Bounds().Size(), def.Image.Bounds().Size(); got != want {
+				t.Errorf("%s: frame size %v want %v", name, got, want)
+			}
+			if got, want := th.FrameCenter(), def.Middle.Sub(def.Image.Bounds().Min); got != want {
+				t.Errorf("%s: frame center %v (manifest %v) want %v", name, got, m.FrameCenter, want)
+			}
+		}
+		if err := ThemeInit(dir, frame, "", false); err == nil || !strings.Contains(err.Error(), "--force") {
+			t.Errorf("%s: Expected a second run without --force to be refused got %v", name, err)
+		}
+		if err := ThemeInit(dir, frame, "", true); err != nil {
+			t.Errorf("%s: Expected --force to overwrite the theme got %v", name, err)
+		}
+	}
+}
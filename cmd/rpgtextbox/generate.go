@@ -35,6 +35,9 @@ type Generate struct {
 	frame         string
 	pattern       string
 	fontColor     string
+	scriptSource  string
+	markup        bool
+	minSize       string
 	SubCommands   map[string]Cmd
 	CommandAction func(c *Generate) error
 }
@@ -254,6 +257,39 @@ func (c *Generate) Execute(args []string) error {
 					}
 				}
 				c.fontColor = value
+
+			case "scriptSource", "script":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.scriptSource = value
+
+			case "markup":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.markup = b
+				} else {
+					c.markup = true
+				}
+
+			case "minSize", "min-size":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.minSize = value
 			case "help", "h":
 				c.Usage()
 				return nil
@@ -310,12 +346,18 @@ func (c *RootCmd) NewGenerate() *Generate {
 
 	set.StringVar(&v.pattern, "pattern", "", "Use help for list")
 
-	set.StringVar(&v.fontColor, "font-color", "black", "Text font color e.g. white black")
+	set.StringVar(&v.fontColor, "font-color", "", "Text font color e.g. white black, default the theme's")
+
+	set.StringVar(&v.scriptSource, "script", "", "Dialogue script to render as a conversation, not with --text, --markup or --min-size")
+
+	set.BoolVar(&v.markup, "markup", false, "Parse {b}, {color=red}, {img=name} etc. markup in --text")
+
+	set.StringVar(&v.minSize, "min-size", "", "Shrink the font as far as this size so --text fits on one page")
 	set.Usage = v.Usage
 
 	v.CommandAction = func(c *Generate) error {
 
-		err := cli.GenerateTextBox(c.width, c.height, c.themeDir, c.fontName, c.dpi, c.fontSize, c.textSource, c.outPrefix, c.chevronLoc, c.avatarPos, c.avatarScale, c.animation, c.frame, c.pattern, c.fontColor)
+		err := cli.GenerateTextBox(c.width, c.height, c.themeDir, c.fontName, c.dpi, c.fontSize, c.textSource, c.outPrefix, c.chevronLoc, c.avatarPos, c.avatarScale, c.animation, c.frame, c.pattern, c.fontColor, c.scriptSource, c.markup, c.minSize)
 		if err != nil {
 			if errors.Is(err, cmd.ErrPrintHelp) {
 				c.Usage()
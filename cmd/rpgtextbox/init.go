@@ -0,0 +1,192 @@
+// Generated by github.com/arran4/go-subcommand/cmd/gosubc
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"errors"
+
+	"github.com/arran4/golang-rpg-textbox/cli"
+	"github.com/arran4/golang-rpg-textbox/cmd"
+)
+
+var _ Cmd = (*Init)(nil)
+
+type Init struct {
+	*Theme
+	Flags         *flag.FlagSet
+	themeDir      string
+	frame         string
+	pattern       string
+	force         bool
+	SubCommands   map[string]Cmd
+	CommandAction func(c *Init) error
+}
+
+type UsageDataInit struct {
+	*Init
+	Recursive bool
+}
+
+func (c *Init) Usage() {
+	err := executeUsage(os.Stderr, "init_usage.txt", UsageDataInit{c, false})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Init) UsageRecursive() {
+	err := executeUsage(os.Stderr, "init_usage.txt", UsageDataInit{c, true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Init) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name := arg
+			value := ""
+			hasValue := false
+			if strings.Contains(arg, "=") {
+				parts := strings.SplitN(arg, "=", 2)
+				name = parts[0]
+				value = parts[1]
+				hasValue = true
+			}
+			trimmedName := strings.TrimLeft(name, "-")
+			switch trimmedName {
+
+			case "themedir":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.themeDir = value
+			case "frame":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.frame = value
+			case "pattern":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.pattern = value
+			case "force":
+				if hasValue {
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return fmt.Errorf("invalid boolean value for flag %s: %s", name, value)
+					}
+					c.force = b
+				} else {
+					c.force = true
+				}
+			case "help", "h":
+				c.Usage()
+				return nil
+			default:
+				return fmt.Errorf("unknown flag: %s", name)
+			}
+		}
+	}
+
+	if c.CommandAction != nil {
+		if err := c.CommandAction(c); err != nil {
+			return fmt.Errorf("init failed: %w", err)
+		}
+	} else {
+		c.Usage()
+	}
+
+	return nil
+}
+
+func (c *Theme) NewInit() *Init {
+	set := flag.NewFlagSet("init", flag.ContinueOnError)
+	v := &Init{
+		Theme:       c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.StringVar(&v.themeDir, "themedir", "./theme", "Directory to create the theme in")
+	set.StringVar(&v.frame, "frame", "", "Use help for list")
+	set.StringVar(&v.pattern, "pattern", "", "Use help for list")
+	set.BoolVar(&v.force, "force", false, "Overwrite an existing theme")
+	set.Usage = v.Usage
+
+	v.CommandAction = func(c *Init) error {
+
+		err := cli.ThemeInit(c.themeDir, c.frame, c.pattern, c.force)
+		if err != nil {
+			if errors.Is(err, cmd.ErrPrintHelp) {
+				c.Usage()
+				return nil
+			}
+			if errors.Is(err, cmd.ErrHelp) {
+				fmt.Fprintf(os.Stderr, "Use '%s help' for more information.\n", os.Args[0])
+				return nil
+			}
+			return fmt.Errorf("init failed: %w", err)
+		}
+		return nil
+	}
+
+	v.SubCommands["help"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	v.SubCommands["usage"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	return v
+}
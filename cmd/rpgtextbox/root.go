@@ -81,6 +81,9 @@ func (c *RootCmd) UsageRecursive() {
 	fmt.Fprintf(os.Stderr, "    %s\n", "skill list")
 	fmt.Fprintf(os.Stderr, "    %s\n", "skill remove")
 	fmt.Fprintf(os.Stderr, "    %s\n", "skill update")
+	fmt.Fprintf(os.Stderr, "    %s\n", "theme")
+	fmt.Fprintf(os.Stderr, "    %s\n", "theme init")
+	fmt.Fprintf(os.Stderr, "    %s\n", "theme validate")
 }
 
 func NewRoot(name, version, commit, date string) (*RootCmd, error) {
@@ -96,6 +99,7 @@ func NewRoot(name, version, commit, date string) (*RootCmd, error) {
 	c.Commands["generate"] = c.NewGenerate()
 	c.Commands["samples"] = c.NewSamples()
 	c.Commands["skill"] = c.NewSkill()
+	c.Commands["theme"] = c.NewTheme()
 	c.Commands["help"] = &InternalCommand{
 		Exec: func(args []string) error {
 			for _, arg := range args {
@@ -0,0 +1,108 @@
+// Generated by github.com/arran4/go-subcommand/cmd/gosubc
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var _ Cmd = (*Theme)(nil)
+
+type Theme struct {
+	*RootCmd
+	Flags         *flag.FlagSet
+	SubCommands   map[string]Cmd
+	CommandAction func(c *Theme) error
+}
+
+type UsageDataTheme struct {
+	*Theme
+	Recursive bool
+}
+
+func (c *Theme) Usage() {
+	err := executeUsage(os.Stderr, "theme_usage.txt", UsageDataTheme{c, false})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Theme) UsageRecursive() {
+	err := executeUsage(os.Stderr, "theme_usage.txt", UsageDataTheme{c, true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Theme) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name := arg
+			trimmedName := strings.TrimLeft(name, "-")
+			switch trimmedName {
+			case "help", "h":
+				c.Usage()
+				return nil
+			default:
+				return fmt.Errorf("unknown flag: %s", name)
+			}
+		}
+	}
+
+	c.Usage()
+
+	return nil
+}
+
+func (c *RootCmd) NewTheme() *Theme {
+	set := flag.NewFlagSet("theme", flag.ContinueOnError)
+	v := &Theme{
+		RootCmd:     c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+	set.Usage = v.Usage
+
+	v.SubCommands["init"] = v.NewInit()
+	v.SubCommands["validate"] = v.NewValidate()
+
+	v.SubCommands["help"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	v.SubCommands["usage"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	return v
+}
@@ -0,0 +1,167 @@
+// Generated by github.com/arran4/go-subcommand/cmd/gosubc
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"errors"
+
+	"github.com/arran4/golang-rpg-textbox/cli"
+	"github.com/arran4/golang-rpg-textbox/cmd"
+)
+
+var _ Cmd = (*Validate)(nil)
+
+type Validate struct {
+	*Theme
+	Flags         *flag.FlagSet
+	themeDir      string
+	format        string
+	SubCommands   map[string]Cmd
+	CommandAction func(c *Validate) error
+}
+
+type UsageDataValidate struct {
+	*Validate
+	Recursive bool
+}
+
+func (c *Validate) Usage() {
+	err := executeUsage(os.Stderr, "validate_usage.txt", UsageDataValidate{c, false})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Validate) UsageRecursive() {
+	err := executeUsage(os.Stderr, "validate_usage.txt", UsageDataValidate{c, true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *Validate) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			name := arg
+			value := ""
+			hasValue := false
+			if strings.Contains(arg, "=") {
+				parts := strings.SplitN(arg, "=", 2)
+				name = parts[0]
+				value = parts[1]
+				hasValue = true
+			}
+			trimmedName := strings.TrimLeft(name, "-")
+			switch trimmedName {
+
+			case "themedir":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.themeDir = value
+			case "format":
+				if !hasValue {
+					if i+1 < len(args) {
+						value = args[i+1]
+						i++
+					} else {
+						return fmt.Errorf("flag %s requires a value", name)
+					}
+				}
+				c.format = value
+			case "help", "h":
+				c.Usage()
+				return nil
+			default:
+				return fmt.Errorf("unknown flag: %s", name)
+			}
+		}
+	}
+
+	if c.CommandAction != nil {
+		if err := c.CommandAction(c); err != nil {
+			return fmt.Errorf("validate failed: %w", err)
+		}
+	} else {
+		c.Usage()
+	}
+
+	return nil
+}
+
+func (c *Theme) NewValidate() *Validate {
+	set := flag.NewFlagSet("validate", flag.ContinueOnError)
+	v := &Validate{
+		Theme:       c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.StringVar(&v.themeDir, "themedir", "./theme", "Directory of the theme to check")
+	set.StringVar(&v.format, "format", "text", "Format of the output, text or json")
+	set.Usage = v.Usage
+
+	v.CommandAction = func(c *Validate) error {
+
+		err := cli.ThemeValidate(c.themeDir, c.format)
+		if err != nil {
+			if errors.Is(err, cmd.ErrPrintHelp) {
+				c.Usage()
+				return nil
+			}
+			if errors.Is(err, cmd.ErrHelp) {
+				fmt.Fprintf(os.Stderr, "Use '%s help' for more information.\n", os.Args[0])
+				return nil
+			}
+			return fmt.Errorf("validate failed: %w", err)
+		}
+		return nil
+	}
+
+	v.SubCommands["help"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	v.SubCommands["usage"] = &InternalCommand{
+		Exec: func(args []string) error {
+			for _, arg := range args {
+				if arg == "-deep" {
+					v.UsageRecursive()
+					return nil
+				}
+			}
+			v.Usage()
+			return nil
+		},
+		UsageFunc: v.Usage,
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+package dialogue
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"time"
+
+	rpgtextbox "github.com/arran4/golang-rpg-textbox"
+	"github.com/arran4/golang-rpg-textbox/theme"
+	wordwrap "github.com/arran4/golang-wordwrap"
+)
+
+// Conversation is a sequence of text boxes, one per Line of a Script, drawn one after another
+type Conversation struct {
+	boxes   []*rpgtextbox.TextBox
+	current int
+}
+
+// NewConversation creates a text box for every line of the script. options are applied to every text box before the
+// line's own options, they must not be stateful (such as an AnimationMode) use the script's animation instead.
+func NewConversation(th theme.Theme, s *Script, destSize image.Point, options ...rpgtextbox.Option) (*Conversation, error) {
+	if len(s.Lines) == 0 {
+		return nil, errors.New("script has no lines")
+	}
+	for _, o := range options {
+		if _, ok := o.(rpgtextbox.AnimationMode); ok {
+			return nil, errors.New("animations can not be shared between lines, set it in the script")
+		}
+	}
+	c := &Conversation{}
+	for _, l := range s.Lines {
+		ops, err := l.Options(s.Dir)
+		if err != nil {
+			return nil, err
+		}
+		tb, err := rpgtextbox.NewSimpleTextBox(th, l.Text, destSize, append(append([]rpgtextbox.Option{}, options...), ops...)...)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", l.LineNumber, err)
+		}
+		c.boxes = append(c.boxes, tb)
+	}
+	return c, nil
+}
+
+// Len is the number of text boxes in the conversation
+func (c *Conversation) Len() int {
+	return len(c.boxes)
+}
+
+// Current is the index of the text box currently being drawn
+func (c *Conversation) Current() int {
+	return c.current
+}
+
+// TextBox returns the i'th text box, for instance to call CalculateAllPages or SetSpaceMap on it
+func (c *Conversation) TextBox(i int) *rpgtextbox.TextBox {
+	return c.boxes[i]
+}
+
+// DrawNextFrame draws the next frame of the current text box moving on to the next text box when it has finished. The
+// return values are the same as rpgtextbox.TextBox's DrawNextFrame except lastPage is only true on the last page of the
+// last text box. The conversation is over when lastPage = true, userInputAccepted = false, wait = -1
+func (c *Conversation) DrawNextFrame(target wordwrap.Image) (lastPage bool, userInputAccepted bool, wait time.Duration, err error) {
+	for c.current < len(c.boxes) {
+		lastPage, userInputAccepted, wait, err = c.boxes[c.current].DrawNextFrame(target)
+		if err != nil {
+			return
+		}
+		if lastPage && !userInputAccepted && wait < 0 {
+			c.current++
+			continue
+		}
+		lastPage = lastPage && c.current == len(c.boxes)-1 && !c.boxes[c.current].HasNext()
+		return
+	}
+	return true, false, -1, nil
+}
+
+// Continue moves on from an animated chevron on the current text box, see rpgtextbox.TextBox's Continue
+func (c *Conversation) Continue() {
+	if c.current < len(c.boxes) {
+		c.boxes[c.current].Continue()
+	}
+}
+
+// Skip completes the animation of the current page of the current text box
+func (c *Conversation) Skip() {
+	if c.current < len(c.boxes) {
+		c.boxes[c.current].Skip()
+	}
+}
+
+// DrawNextPageFrame draws the next page of the current text box ignoring animation, moving on to the next text box when
+// it has run out of pages. Returns false when there is nothing left to draw.
+func (c *Conversation) DrawNextPageFrame(target wordwrap.Image, opts ...wordwrap.DrawOption) (bool, error) {
+	for c.current < len(c.boxes) {
+		drawn, err := c.boxes[c.current].DrawNextPageFrame(target, opts...)
+		if err != nil {
+			return false, err
+		}
+		if drawn {
+			return true, nil
+		}
+		c.current++
+	}
+	return false, nil
+}
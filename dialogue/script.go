@@ -0,0 +1,250 @@
+package dialogue
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	rpgtextbox "github.com/arran4/golang-rpg-textbox"
+	"github.com/arran4/golang-rpg-textbox/util"
+)
+
+// AvatarLocations maps the names usable in a script to rpgtextbox.AvatarLocations
+var AvatarLocations = map[string]rpgtextbox.AvatarLocations{
+	"no-avatar":              rpgtextbox.NoAvatar,
+	"left-avatar":            rpgtextbox.LeftAvatar,
+	"right-avatar":           rpgtextbox.RightAvatar,
+	"left-avatar-panel":      rpgtextbox.LeftAvatarPanel,
+	"right-avatar-panel":     rpgtextbox.RightAvatarPanel,
+	"top-left-avatar-panel":  rpgtextbox.TopLeftAvatarPanel,
+	"top-right-avatar-panel": rpgtextbox.TopRightAvatarPanel,
+}
+
+// NamePositions maps the names usable in a script to rpgtextbox.NamePositions
+var NamePositions = map[string]rpgtextbox.NamePositions{
+	"no-name":                           rpgtextbox.NoName,
+	"name-top-left-above-text-in-frame": rpgtextbox.NameTopLeftAboveTextInFrame,
+	"name-top-center-in-frame":          rpgtextbox.NameTopCenterInFrame,
+	"name-left-above-avatar-in-frame":   rpgtextbox.NameLeftAboveAvatarInFrame,
+	"name-top-left-above-frame":         rpgtextbox.NameTopLeftAboveFrame,
+	"name-top-center-above-frame":       rpgtextbox.NameTopCenterAboveFrame,
+	"name-top-left-on-frame":            rpgtextbox.NameTopLeftOnFrame,
+	"name-top-center-on-frame":          rpgtextbox.NameTopCenterOnFrame,
+	"name-top-right-on-frame":           rpgtextbox.NameTopRightOnFrame,
+}
+
+// Animations maps the names usable in a script to constructors for the animation. Animations hold the position within
+// a text box so each line needs its own.
+var Animations = map[string]func() rpgtextbox.AnimationMode{
+	"no-animation":               func() rpgtextbox.AnimationMode { return nil },
+	"fade-animation":             func() rpgtextbox.AnimationMode { return rpgtextbox.NewFadeAnimation() },
+	"box-by-box-animation":       func() rpgtextbox.AnimationMode { return rpgtextbox.NewBoxByBoxAnimation() },
+	"letter-by-letter-animation": func() rpgtextbox.AnimationMode { return rpgtextbox.NewLetterByLetterAnimation() },
+	"scroll-animation":           func() rpgtextbox.AnimationMode { return rpgtextbox.NewScrollAnimation() },
+}
+
+// Attribute names usable inside the [] of a speaker line or after an @ for a default.
+const (
+	AvatarAttribute         = "avatar"
+	AvatarLocationAttribute = "avatar-pos"
+	NamePositionAttribute   = "name-pos"
+	AnimationAttribute      = "animation"
+)
+
+// Line is a single speech (text box) in a Script
+type Line struct {
+	// Speaker is the name shown in the name tag, empty for narration
+	Speaker string
+	// Text is the content of the text box
+	Text string
+	// Avatar is the path of an avatar image, relative to the Script's Dir, empty for the theme's avatar
+	Avatar string
+	// AvatarLocation is a key of AvatarLocations
+	AvatarLocation string
+	// NamePosition is a key of NamePositions
+	NamePosition string
+	// Animation is a key of Animations
+	Animation string
+	// LineNumber is where in the script the line starts
+	LineNumber int
+}
+
+// Script is a parsed dialogue script. The format is line based:
+//
+//	# Comments start with a hash
+//	@animation=letter-by-letter-animation
+//	Alice [avatar=alice.png; avatar-pos=left-avatar; name-pos=name-top-left-above-frame]: Hello there!
+//	Bob: Hi Alice.
+//	    Lines starting with white space continue the previous text on a new line.
+//	: A line with no speaker is narration.
+//
+// Attributes in [] apply to that line only, @ lines set the default for all following lines.
+type Script struct {
+	// Lines in order of appearance
+	Lines []*Line
+	// Dir is the directory relative avatar paths are resolved against
+	Dir string
+}
+
+// ParseFile parses the dialogue script at fn, avatars are resolved relative to the file
+func ParseFile(fn string) (*Script, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, fmt.Errorf("opening script: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	s, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", fn, err)
+	}
+	s.Dir = filepath.Dir(fn)
+	return s, nil
+}
+
+// Parse parses a dialogue script, see Script for the format
+func Parse(r io.Reader) (*Script, error) {
+	s := &Script{}
+	defaults := &Line{}
+	var last *Line
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "":
+			last = nil
+		case strings.HasPrefix(trimmed, "#"):
+		case strings.HasPrefix(trimmed, "@"):
+			k, v, _ := strings.Cut(trimmed[1:], "=")
+			if err := defaults.set(strings.TrimSpace(k), strings.TrimSpace(v)); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			last = nil
+		case text[0] == ' ' || text[0] == '\t':
+			if last == nil {
+				return nil, fmt.Errorf("line %d: continuation without a preceding speaker line", lineNumber)
+			}
+			last.Text += "\n" + trimmed
+		default:
+			l := *defaults
+			l.LineNumber = lineNumber
+			if err := l.parse(text); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			s.Lines = append(s.Lines, &l)
+			last = &l
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading script: %w", err)
+	}
+	return s, nil
+}
+
+// parse reads a "Speaker [attributes]: text" line into l
+func (l *Line) parse(text string) error {
+	head, body, found := strings.Cut(text, ":")
+	if !found {
+		return fmt.Errorf("expected \"speaker: text\" got %q", text)
+	}
+	if start := strings.Index(head, "["); start >= 0 {
+		end := strings.LastIndex(head, "]")
+		if end < start {
+			return fmt.Errorf("unterminated attributes in %q", head)
+		}
+		for _, attr := range strings.Split(head[start+1:end], ";") {
+			if strings.TrimSpace(attr) == "" {
+				continue
+			}
+			k, v, _ := strings.Cut(attr, "=")
+			if err := l.set(strings.TrimSpace(k), strings.TrimSpace(v)); err != nil {
+				return err
+			}
+		}
+		head = head[:start]
+	}
+	l.Speaker = strings.TrimSpace(head)
+	l.Text = strings.TrimSpace(body)
+	return nil
+}
+
+// set sets an attribute by name, checking the value is known
+func (l *Line) set(key, value string) error {
+	switch key {
+	case AvatarAttribute:
+		l.Avatar = value
+	case AvatarLocationAttribute:
+		if _, ok := AvatarLocations[value]; !ok {
+			return fmt.Errorf("unknown %s %q, expected one of: %s", key, value, keys(AvatarLocations))
+		}
+		l.AvatarLocation = value
+	case NamePositionAttribute:
+		if _, ok := NamePositions[value]; !ok {
+			return fmt.Errorf("unknown %s %q, expected one of: %s", key, value, keys(NamePositions))
+		}
+		l.NamePosition = value
+	case AnimationAttribute:
+		if _, ok := Animations[value]; !ok {
+			return fmt.Errorf("unknown %s %q, expected one of: %s", key, value, keys(Animations))
+		}
+		l.Animation = value
+	default:
+		return fmt.Errorf("unknown attribute %q", key)
+	}
+	return nil
+}
+
+// Options converts the line into rpgtextbox options. Avatars are loaded relative to dir.
+func (l *Line) Options(dir string) ([]rpgtextbox.Option, error) {
+	var ops []rpgtextbox.Option
+	if l.Avatar != "" {
+		fn := l.Avatar
+		if !filepath.IsAbs(fn) {
+			fn = filepath.Join(dir, fn)
+		}
+		i, err := util.LoadImageFile(fn)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: loading avatar %s: %w", l.LineNumber, fn, err)
+		}
+		ops = append(ops, rpgtextbox.Avatar(i))
+		if l.AvatarLocation == "" {
+			ops = append(ops, rpgtextbox.LeftAvatar)
+		}
+	}
+	if l.AvatarLocation != "" {
+		ops = append(ops, AvatarLocations[l.AvatarLocation])
+	}
+	if l.Speaker != "" {
+		ops = append(ops, rpgtextbox.Name(l.Speaker))
+		if l.NamePosition == "" {
+			ops = append(ops, rpgtextbox.NameTopLeftAboveTextInFrame)
+		}
+	}
+	if l.NamePosition != "" {
+		ops = append(ops, NamePositions[l.NamePosition])
+	}
+	if l.Animation != "" {
+		if a := Animations[l.Animation](); a != nil {
+			ops = append(ops, a)
+		}
+	}
+	return ops, nil
+}
+
+// keys lists the keys of a map sorted for error messages
+func keys[V any](m map[string]V) string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return strings.Join(ks, ", ")
+}
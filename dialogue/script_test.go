@@ -0,0 +1,88 @@
+package dialogue
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+)
+
+const testScript = `# A test script
+@animation=box-by-box-animation
+Alice [avatar-pos=left-avatar; name-pos=name-top-left-above-frame]: Hello there!
+    How are you?
+
+Bob [animation=no-animation]: Fine: thanks.
+: The end.
+`
+
+func TestParse(t *testing.T) {
+	s, err := Parse(strings.NewReader(testScript))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	want := []Line{
+		{Speaker: "Alice", Text: "Hello there!\nHow are you?", AvatarLocation: "left-avatar", NamePosition: "name-top-left-above-frame", Animation: "box-by-box-animation", LineNumber: 3},
+		{Speaker: "Bob", Text: "Fine: thanks.", Animation: "no-animation", LineNumber: 6},
+		{Speaker: "", Text: "The end.", Animation: "box-by-box-animation", LineNumber: 7},
+	}
+	if len(s.Lines) != len(want) {
+		t.Fatalf("got %d lines want %d", len(s.Lines), len(want))
+	}
+	for i, l := range s.Lines {
+		if *l != want[i] {
+			t.Errorf("line %d: got %+v want %+v", i, *l, want[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, script := range []string{
+		"No colon here",
+		"  continuation first",
+		"Alice [mood=happy]: Hi",
+		"Alice [animation=spin]: Hi",
+		"@name-pos=floating",
+	} {
+		if _, err := Parse(strings.NewReader(script)); err == nil {
+			t.Errorf("Expected error parsing %q", script)
+		}
+	}
+}
+
+func TestConversation(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	s, err := Parse(strings.NewReader(testScript))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	size := image.Pt(600, 150)
+	c, err := NewConversation(th, s, size)
+	if err != nil {
+		t.Fatalf("NewConversation error: %v", err)
+	}
+	if c.Len() != 3 {
+		t.Fatalf("got %d text boxes want 3", c.Len())
+	}
+	seen := map[int]bool{}
+	for f := 0; ; f++ {
+		if f > 1000 {
+			t.Fatalf("Conversation never finished")
+		}
+		seen[c.Current()] = true
+		lastPage, ui, wait, err := c.DrawNextFrame(image.NewRGBA(image.Rect(0, 0, size.X, size.Y)))
+		if err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+		if lastPage && !ui && wait < 0 {
+			break
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected all 3 text boxes to be drawn, got %v", seen)
+	}
+}
@@ -0,0 +1,137 @@
+package rpgtextbox
+
+import (
+	"image"
+
+	wordwrap "github.com/arran4/golang-wordwrap"
+)
+
+// Face is an expression name, such as "happy" or "angry", for an AvatarProvider. As an Option it is the expression the
+// text box starts with, as the ID of a box (which {face=} markup inserts) the expression changes when the box is
+// revealed.
+type Face string
+
+// apply Set the starting expression when used as an Option
+func (f Face) apply(box *TextBox) {
+	box.face = f
+}
+
+// AvatarProvider supplies avatar images that change with the speaker's expression. All the images should be the same
+// size as the layout is calculated from the starting expression.
+type AvatarProvider interface {
+	// AvatarFrame returns the image for expression. talking is true while letters are being revealed, frame counts the
+	// frames drawn while talking so the mouth can move.
+	AvatarFrame(expression string, talking bool, frame int) image.Image
+}
+
+// avatarProvider holds the AvatarProvider option
+type avatarProvider struct {
+	AvatarProvider
+}
+
+// ExpressionAvatar draws the avatar from p instead of the theme, use Face and {face=} markup to change the expression.
+func ExpressionAvatar(p AvatarProvider) Option {
+	return &avatarProvider{
+		AvatarProvider: p,
+	}
+}
+
+// apply Set the avatar provider when used as an Option
+func (ap *avatarProvider) apply(box *TextBox) {
+	box.avatarProvider = ap.AvatarProvider
+}
+
+// AvatarFrames are the images of one expression
+type AvatarFrames struct {
+	// Idle is shown while the character isn't talking
+	Idle image.Image
+	// Talking are shown in turn while the character is talking, Idle is shown if there are none
+	Talking []image.Image
+}
+
+// AvatarSet is an AvatarProvider made from the images of each expression. The expression "" is used for expressions
+// which aren't in the set.
+type AvatarSet map[string]AvatarFrames
+
+// Enforce the interface
+var _ AvatarProvider = AvatarSet(nil)
+
+// AvatarFrame returns the Idle image, or the Talking image for frame if talking
+func (as AvatarSet) AvatarFrame(expression string, talking bool, frame int) image.Image {
+	af, ok := as[expression]
+	if !ok {
+		af = as[""]
+	}
+	if talking && len(af.Talking) > 0 {
+		return af.Talking[frame%len(af.Talking)]
+	}
+	return af.Idle
+}
+
+// faceTracker follows the expression through the boxes as they are drawn, and whether letters are still being revealed
+type faceTracker struct {
+	face Face
+	// boxes is how many boxes have been drawn
+	boxes int
+	// last is the last box drawn
+	last wordwrap.Box
+}
+
+// record is a wordwrap.BoxRecorder which notes the boxes drawn
+func (ft *faceTracker) record(box wordwrap.Box, min, max image.Point, bps *wordwrap.BoxPositionStats) {
+	ft.boxes++
+	ft.last = box
+	if f, ok := boxID(box).(Face); ok {
+		ft.face = f
+	}
+}
+
+// talking returns true if only some of the boxes in ls were drawn, unless the text is stopped at a Pause
+func (ft *faceTracker) talking(ls []wordwrap.Line) bool {
+	if _, ok := boxID(ft.last).(Pause); ok {
+		return false
+	}
+	if _, ok := ft.last.(*partialBox); ok {
+		return true
+	}
+	total := 0
+	for _, l := range ls {
+		total += len(l.Boxes())
+	}
+	return ft.boxes < total
+}
+
+// faceBefore is the expression at the start of line l
+func (tb *TextBox) faceBefore(l wordwrap.Line) Face {
+	face := tb.face
+	for _, p := range tb.pages {
+		for _, pl := range p.ls {
+			if pl == l {
+				return face
+			}
+			for _, b := range pl.Boxes() {
+				if f, ok := boxID(b).(Face); ok {
+					face = f
+				}
+			}
+		}
+	}
+	return face
+}
+
+// avatarImage returns the avatar to draw, from the AvatarProvider if there is one
+func (tb *TextBox) avatarImage(face Face, talking bool) image.Image {
+	if tb.avatarProvider == nil {
+		return tb.Avatar()
+	}
+	frame := tb.talkFrame
+	if talking {
+		tb.talkFrame++
+	} else {
+		tb.talkFrame = 0
+	}
+	if i := tb.avatarProvider.AvatarFrame(string(face), talking, frame); i != nil {
+		return i
+	}
+	return tb.Avatar()
+}
@@ -0,0 +1,99 @@
+package rpgtextbox
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+)
+
+func TestAvatarExpressions(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	solid := func(r, g, b uint8) image.Image {
+		i := image.NewRGBA(image.Rect(0, 0, 40, 40))
+		for p := 0; p < len(i.Pix); p += 4 {
+			i.Pix[p], i.Pix[p+1], i.Pix[p+2], i.Pix[p+3] = r, g, b, 255
+		}
+		return i
+	}
+	set := AvatarSet{
+		"":      {Idle: solid(0, 0, 255)},
+		"happy": {Idle: solid(0, 255, 0), Talking: []image.Image{solid(0, 200, 0), solid(0, 100, 0)}},
+		"angry": {Idle: solid(255, 0, 0), Talking: []image.Image{solid(200, 0, 0), solid(100, 0, 0)}},
+	}
+	if c := set.AvatarFrame("sad", true, 3).At(0, 0); c != (color.RGBA{B: 255, A: 255}) {
+		t.Errorf("Expected an unknown expression to use the default got %v", c)
+	}
+	size := image.Pt(400, 150)
+	text := "Hello {face=angry}there"
+	t.Run("Animated", func(t *testing.T) {
+		tb, err := NewMarkupTextBox(th, text, size, LeftAvatar, ExpressionAvatar(set), Face("happy"), NewLetterByLetterAnimation())
+		if err != nil {
+			t.Fatalf("Error creating text box: %v", err)
+		}
+		l, err := tb.newLayout(image.Rectangle{Max: size})
+		if err != nil {
+			t.Fatalf("Error creating layout: %v", err)
+		}
+		p := l.AvatarRect().Min.Add(image.Pt(1, 1))
+		var seen []color.Color
+		for f := 0; ; f++ {
+			if f > 100 {
+				t.Fatalf("Animation never finished")
+			}
+			i := image.NewRGBA(image.Rectangle{Max: size})
+			lastPage, ui, _, err := tb.DrawNextFrame(i)
+			if err != nil {
+				t.Fatalf("Draw next frame error: %v", err)
+			}
+			if c := i.At(p.X, p.Y); len(seen) == 0 || seen[len(seen)-1] != c {
+				seen = append(seen, c)
+			}
+			if lastPage && ui {
+				break
+			}
+		}
+		want := []color.Color{
+			color.RGBA{G: 200, A: 255}, color.RGBA{G: 100, A: 255},
+			color.RGBA{R: 200, A: 255}, color.RGBA{R: 100, A: 255},
+			color.RGBA{R: 255, A: 255},
+		}
+		for _, c := range want {
+			found := false
+			for _, s := range seen {
+				found = found || s == c
+			}
+			if !found {
+				t.Errorf("Expected the avatar to be %v at some point, saw %v", c, seen)
+			}
+		}
+		if seen[len(seen)-1] != want[len(want)-1] {
+			t.Errorf("Expected the avatar to stop talking when the text is shown, ended with %v", seen[len(seen)-1])
+		}
+	})
+	t.Run("Static", func(t *testing.T) {
+		tb, err := NewMarkupTextBox(th, text, size, LeftAvatar, ExpressionAvatar(set), Face("happy"))
+		if err != nil {
+			t.Fatalf("Error creating text box: %v", err)
+		}
+		if c := tb.Avatar().At(0, 0); c != (color.RGBA{G: 255, A: 255}) {
+			t.Errorf("Expected the starting expression for the layout got %v", c)
+		}
+		l, err := tb.newLayout(image.Rectangle{Max: size})
+		if err != nil {
+			t.Fatalf("Error creating layout: %v", err)
+		}
+		i := image.NewRGBA(image.Rectangle{Max: size})
+		if _, err := tb.DrawNextPageFrame(i); err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+		p := l.AvatarRect().Min.Add(image.Pt(1, 1))
+		if c := i.At(p.X, p.Y); c != (color.RGBA{R: 255, A: 255}) {
+			t.Errorf("Expected the expression at the end of the page got %v", c)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package rpgtextbox
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/util"
+	wordwrap "github.com/arran4/golang-wordwrap"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+// ShrinkToFit is an Option which reduces the font size until all the text fits on one page. Sizes are tried from
+// MaxSize down to MinSize in Steps, rebuilding the face each time the same way util.GetFontFace does. It replaces the
+// theme's font face for the text only, faces given as part of rich text (such as by Markup) keep their size. The text
+// is fitted to the size given to NewRichTextBox.
+type ShrinkToFit struct {
+	// Font is the font the face is built from, it should be the same font as the theme's face
+	Font *truetype.Font
+	// MaxSize is the size to start at, usually the size of the theme's face
+	MaxSize float64
+	// MinSize is the smallest size to try
+	MinSize float64
+	// Step is how much the size is reduced by each attempt
+	Step float64
+	// DPI the face is rendered at
+	DPI float64
+	// Paginate if true uses MinSize and splits the text into pages when it doesn't fit, otherwise creating the text box
+	// fails
+	Paginate bool
+	// size is the size chosen
+	size float64
+}
+
+// NewShrinkToFit creates a ShrinkToFit which starts at maxSize and goes down half a point at a time to minSize
+func NewShrinkToFit(f *truetype.Font, maxSize, minSize, dpi float64) *ShrinkToFit {
+	return &ShrinkToFit{
+		Font:    f,
+		MaxSize: maxSize,
+		MinSize: minSize,
+		Step:    0.5,
+		DPI:     dpi,
+	}
+}
+
+// apply Set the shrink to fit when used as an Option
+func (s *ShrinkToFit) apply(box *TextBox) {
+	box.shrinkToFit = s
+}
+
+// FontSize is the size that was chosen, 0 until the text box has been created
+func (s *ShrinkToFit) FontSize() float64 {
+	return s.size
+}
+
+// wrapper finds the largest size the text fits into layout's text rect at and creates a word wrapper for it. args are
+// the word wrapper's arguments, the first of which is the font drawer to replace.
+func (s *ShrinkToFit) wrapper(th theme.Theme, layout Layout, args []interface{}) (*wordwrap.SimpleWrapper, error) {
+	if s.Font == nil {
+		return nil, errors.New("shrink to fit requires a font")
+	}
+	if s.MinSize <= 0 || s.MinSize > s.MaxSize {
+		return nil, fmt.Errorf("invalid shrink to fit sizes %v to %v", s.MaxSize, s.MinSize)
+	}
+	step := s.Step
+	if step <= 0 {
+		step = 0.5
+	}
+	for size := s.MaxSize; ; size -= step {
+		if size < s.MinSize {
+			size = s.MinSize
+		}
+		args := s.args(th, size, args)
+		fits, err := fitsOnePage(wordwrap.NewRichWrapper(args...), layout.TextRect())
+		if err != nil {
+			return nil, err
+		}
+		if fits || (size == s.MinSize && s.Paginate) {
+			s.size = size
+			return wordwrap.NewRichWrapper(args...), nil
+		}
+		if size == s.MinSize {
+			return nil, fmt.Errorf("text does not fit on one page at the minimum font size %v", s.MinSize)
+		}
+	}
+}
+
+// args replaces the font drawer at the start of args with one for a face of size
+func (s *ShrinkToFit) args(th theme.Theme, size float64, args []interface{}) []interface{} {
+	fd := &font.Drawer{
+		Face: util.GetFontFace(size, s.DPI, s.Font),
+	}
+	if tfd := th.FontDrawer(); tfd != nil {
+		fd.Src = tfd.Src
+	}
+	return append([]interface{}{fd}, args[1:]...)
+}
+
+// fitsOnePage returns true if the wrapper puts all of its text into r
+func fitsOnePage(w *wordwrap.SimpleWrapper, r image.Rectangle) (bool, error) {
+	if _, _, err := w.TextToRect(r); err != nil {
+		return false, err
+	}
+	return !w.HasNext(), nil
+}
@@ -0,0 +1,52 @@
+package rpgtextbox
+
+import (
+	"image"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	"github.com/arran4/golang-rpg-textbox/util"
+)
+
+func TestShrinkToFit(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	f, err := util.OpenFont("goregular")
+	if err != nil {
+		t.Fatalf("Failed to open font: %v", err)
+	}
+	size := image.Pt(300, 120)
+	text := "A rusty sword. It has seen better days but it is still sharp enough to cut through the undergrowth. The hilt is wrapped in worn leather and the blade is notched in two places."
+	fit := NewShrinkToFit(f, 16, 6, 75)
+	tb, err := NewSimpleTextBox(theme, text, size, fit)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	if fit.FontSize() >= 16 || fit.FontSize() < 6 {
+		t.Errorf("FontSize() = %v want between 6 and 16", fit.FontSize())
+	}
+	if pages, err := tb.CalculateAllPages(size); err != nil {
+		t.Fatalf("Calculate pages error: %v", err)
+	} else if pages != 1 {
+		t.Errorf("Expected the text to fit on one page got %d pages", pages)
+	}
+	if _, err := NewSimpleTextBox(theme, text, size, NewShrinkToFit(f, 16, 12, 75)); err == nil {
+		t.Errorf("Expected an error when the text doesn't fit at the minimum size")
+	}
+	paged := NewShrinkToFit(f, 16, 12, 75)
+	paged.Paginate = true
+	tb, err = NewSimpleTextBox(theme, text, size, paged)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	if paged.FontSize() != 12 {
+		t.Errorf("FontSize() = %v want 12", paged.FontSize())
+	}
+	if pages, err := tb.CalculateAllPages(size); err != nil {
+		t.Fatalf("Calculate pages error: %v", err)
+	} else if pages < 2 {
+		t.Errorf("Expected the text to be split into pages got %d pages", pages)
+	}
+}
@@ -6,7 +6,9 @@ require (
 	github.com/arran4/golang-frame v0.0.7
 	github.com/arran4/golang-wordwrap v0.0.4
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
+	github.com/rivo/uniseg v0.4.7
 	golang.org/x/image v0.41.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/arran4/spacemap v0.0.2
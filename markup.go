@@ -0,0 +1,309 @@
+package rpgtextbox
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/util"
+	wordwrap "github.com/arran4/golang-wordwrap"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+// Pause is the ID of the empty box that {pause=} markup inserts into the text. Animations which reveal the text
+// gradually wait for the duration when they reach it.
+type Pause time.Duration
+
+// Speed is the ID {speed=} markup gives the text inside it. Animations which reveal the text gradually divide their
+// wait by it, so 2 is twice as fast and 0.5 half as fast.
+type Speed float64
+
+// pauseImage is the empty image used for pause and face boxes
+var pauseImage = image.NewRGBA(image.Rectangle{})
+
+// Markup converts text containing {tag} markup into arguments for NewRichTextBox. Supported tags are:
+//
+//	{b}bold{/b} {i}italic{/i} {size=20}larger{/size} {color=red}red{/color} {color=#ff0000}also red{/color}
+//	{img=heart} an inline image
+//	{pause=500ms} a pause for animations
+//	{speed=0.5}slowly{/speed} a change in animation speed
+//	{face=angry} a change of expression for an AvatarProvider
+//
+// Tags which have a closing tag must be closed in the reverse order they were opened. Use {{ for a literal {.
+type Markup struct {
+	// Regular, Bold, Italic and BoldItalic are the fonts used for {b} and {i}
+	Regular, Bold, Italic, BoldItalic *truetype.Font
+	// Size is the font size used when it hasn't been changed with {size=}, it should match the theme's font, see
+	// NewMarkupForTheme
+	Size float64
+	// DPI the fonts are rendered at
+	DPI float64
+	// Images are the images available to {img=name}
+	Images map[string]image.Image
+	// ImageDir if set is searched for name or name.png when {img=name} isn't in Images
+	ImageDir string
+	faces    map[markupFace]font.Face
+}
+
+// markupFace is the key of cached font faces
+type markupFace struct {
+	bold, italic bool
+	size         float64
+}
+
+// markupTag is an open tag and the arguments inside it
+type markupTag struct {
+	name         string
+	args         []interface{}
+	bold, italic bool
+	size         float64
+}
+
+// NewMarkup creates a Markup using the go fonts at the same size as the simple theme
+func NewMarkup() (*Markup, error) {
+	m := &Markup{
+		Size:   16,
+		DPI:    75,
+		Images: map[string]image.Image{},
+	}
+	for _, f := range []struct {
+		dest **truetype.Font
+		name string
+	}{
+		{&m.Regular, "goregular"},
+		{&m.Bold, "gobold"},
+		{&m.Italic, "goitalic"},
+		{&m.BoldItalic, "gobolditalic"},
+	} {
+		var err error
+		if *f.dest, err = util.OpenFont(f.name); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// NewMarkupForTheme creates a Markup using the go fonts at the size and DPI of th's font if it implements
+// theme.FontSized, otherwise at the size of NewMarkup
+func NewMarkupForTheme(th theme.Theme) (*Markup, error) {
+	m, err := NewMarkup()
+	if err != nil {
+		return nil, err
+	}
+	if fs, ok := th.(theme.FontSized); ok && fs.FontSize() > 0 {
+		m.Size = fs.FontSize()
+		if dpi := fs.FontDPI(); dpi > 0 {
+			m.DPI = dpi
+		}
+	}
+	return m, nil
+}
+
+// NewMarkupTextBox creates a TextBox from text containing markup using the defaults of NewMarkupForTheme. Create a
+// Markup and pass the result of Parse to NewRichTextBox to use other fonts or images.
+func NewMarkupTextBox(th theme.Theme, text string, destSize image.Point, options ...Option) (*TextBox, error) {
+	m, err := NewMarkupForTheme(th)
+	if err != nil {
+		return nil, err
+	}
+	args, err := m.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, destSize)
+	for _, o := range options {
+		args = append(args, o)
+	}
+	return NewRichTextBox(th, args...)
+}
+
+// Parse converts text containing markup into arguments for NewRichTextBox
+func (m *Markup) Parse(text string) ([]interface{}, error) {
+	stack := []*markupTag{{size: m.Size}}
+	var sb strings.Builder
+	for i := 0; i < len(text); {
+		top := stack[len(stack)-1]
+		if text[i] != '{' {
+			sb.WriteByte(text[i])
+			i++
+			continue
+		}
+		if strings.HasPrefix(text[i:], "{{") {
+			sb.WriteByte('{')
+			i += 2
+			continue
+		}
+		end := strings.IndexByte(text[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated markup tag at offset %d", i)
+		}
+		tag := text[i+1 : i+end]
+		offset := i
+		i += end + 1
+		if sb.Len() > 0 {
+			top.args = append(top.args, sb.String())
+			sb.Reset()
+		}
+		if name, found := strings.CutPrefix(tag, "/"); found {
+			if len(stack) == 1 || top.name != name {
+				return nil, fmt.Errorf("unexpected {/%s} at offset %d", name, offset)
+			}
+			stack = stack[:len(stack)-1]
+			parent := stack[len(stack)-1]
+			parent.args = append(parent.args, wordwrap.Group{Args: top.args})
+			continue
+		}
+		name, value, hasValue := strings.Cut(tag, "=")
+		if err := m.tag(&stack, name, value, hasValue); err != nil {
+			return nil, fmt.Errorf("markup tag {%s} at offset %d: %w", tag, offset, err)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("unclosed markup tag {%s}", stack[len(stack)-1].name)
+	}
+	if sb.Len() > 0 {
+		stack[0].args = append(stack[0].args, sb.String())
+	}
+	return stack[0].args, nil
+}
+
+// tag handles an opening or stand alone tag
+func (m *Markup) tag(stack *[]*markupTag, name, value string, hasValue bool) error {
+	top := (*stack)[len(*stack)-1]
+	switch name {
+	case "b", "i":
+		if hasValue {
+			return fmt.Errorf("%s does not take a value", name)
+		}
+	case "size", "color", "img", "pause", "speed", "face":
+		if value == "" {
+			return fmt.Errorf("%s requires a value", name)
+		}
+	default:
+		return fmt.Errorf("unknown tag %q", name)
+	}
+	t := &markupTag{
+		name:   name,
+		bold:   top.bold,
+		italic: top.italic,
+		size:   top.size,
+	}
+	switch name {
+	case "img":
+		i, err := m.image(value)
+		if err != nil {
+			return err
+		}
+		top.args = append(top.args, imageContent(i))
+		return nil
+	case "pause":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		top.args = append(top.args, imageContent(pauseImage, wordwrap.WithID(Pause(d))))
+		return nil
+	case "face":
+		top.args = append(top.args, imageContent(pauseImage, wordwrap.WithID(Face(value))))
+		return nil
+	case "color":
+		c, err := util.ParseColor(value)
+		if err != nil {
+			return err
+		}
+		t.args = append(t.args, wordwrap.FontColor{Color: c})
+	case "speed":
+		speed, err := strconv.ParseFloat(value, 64)
+		if err != nil || speed <= 0 {
+			return fmt.Errorf("invalid speed %q", value)
+		}
+		t.args = append(t.args, wordwrap.IDOption{ID: Speed(speed)})
+	case "b":
+		t.bold = true
+	case "i":
+		t.italic = true
+	case "size":
+		size, err := strconv.ParseFloat(value, 64)
+		if err != nil || size <= 0 {
+			return fmt.Errorf("invalid size %q", value)
+		}
+		t.size = size
+	}
+	switch name {
+	case "b", "i", "size":
+		f, err := m.face(t.bold, t.italic, t.size)
+		if err != nil {
+			return err
+		}
+		t.args = append(t.args, f)
+	}
+	*stack = append(*stack, t)
+	return nil
+}
+
+// imageContent creates the content of an inline image. The boxer doesn't calculate the metrics of images given as rich
+// content, which leaves them with no height, so a decorator does it instead.
+func imageContent(i image.Image, options ...wordwrap.ContentOption) *wordwrap.Content {
+	return wordwrap.NewImageContent(i, append([]wordwrap.ContentOption{wordwrap.WithAlignment(wordwrap.AlignBaseline), wordwrap.WithDecorators(func(b wordwrap.Box) wordwrap.Box {
+		if ib, ok := b.(*wordwrap.ImageBox); ok {
+			ib.CalculateMetrics()
+		}
+		return b
+	})}, options...)...)
+}
+
+// face returns the font face for the style, creating it if it hasn't been used before
+func (m *Markup) face(bold, italic bool, size float64) (font.Face, error) {
+	key := markupFace{bold: bold, italic: italic, size: size}
+	if f, ok := m.faces[key]; ok {
+		return f, nil
+	}
+	var tf *truetype.Font
+	switch {
+	case bold && italic:
+		tf = m.BoldItalic
+	case bold:
+		tf = m.Bold
+	case italic:
+		tf = m.Italic
+	default:
+		tf = m.Regular
+	}
+	if tf == nil {
+		return nil, fmt.Errorf("no font for bold=%v italic=%v", bold, italic)
+	}
+	if m.faces == nil {
+		m.faces = map[markupFace]font.Face{}
+	}
+	f := util.GetFontFace(size, m.DPI, tf)
+	m.faces[key] = f
+	return f, nil
+}
+
+// image finds the image for an {img=name} tag
+func (m *Markup) image(name string) (image.Image, error) {
+	if i, ok := m.Images[name]; ok {
+		return i, nil
+	}
+	if m.ImageDir == "" {
+		return nil, fmt.Errorf("unknown image %q", name)
+	}
+	fn := filepath.Join(m.ImageDir, name)
+	if filepath.Ext(fn) == "" {
+		fn += ".png"
+	}
+	i, err := util.LoadImageFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("loading image %q: %w", name, err)
+	}
+	if m.Images == nil {
+		m.Images = map[string]image.Image{}
+	}
+	m.Images[name] = i
+	return i, nil
+}
@@ -0,0 +1,164 @@
+package rpgtextbox
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	wordwrap "github.com/arran4/golang-wordwrap"
+	"golang.org/x/image/font"
+)
+
+func TestMarkupParse(t *testing.T) {
+	m, err := NewMarkup()
+	if err != nil {
+		t.Fatalf("NewMarkup error: %v", err)
+	}
+	heart := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	m.Images["heart"] = heart
+	args, err := m.Parse("Hello {b}{color=red}brave{/color} {i}bold italic{/i}{/b} {img=heart}{{world}{pause=500ms}!")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if len(args) != 7 {
+		t.Fatalf("Expected 7 args got %d: %#v", len(args), args)
+	}
+	if args[0] != "Hello " || args[2] != " " || args[4] != "{world}" || args[6] != "!" {
+		t.Errorf("Unexpected text args %#v", args)
+	}
+	bold, ok := args[1].(wordwrap.Group)
+	if !ok || len(bold.Args) != 4 {
+		t.Fatalf("Expected bold group got %#v", args[1])
+	}
+	if _, ok := bold.Args[0].(font.Face); !ok {
+		t.Errorf("Expected bold group to start with a font face got %#v", bold.Args[0])
+	}
+	red, ok := bold.Args[1].(wordwrap.Group)
+	if !ok || red.Args[0] != (wordwrap.FontColor{Color: color.RGBA{R: 0xff, A: 0xff}}) || red.Args[1] != "brave" {
+		t.Errorf("Expected red group got %#v", bold.Args[1])
+	}
+	if _, ok := args[3].(*wordwrap.Content); !ok {
+		t.Errorf("Expected heart image got %#v", args[3])
+	}
+	if _, ok := args[5].(*wordwrap.Content); !ok {
+		t.Errorf("Expected pause got %#v", args[5])
+	}
+}
+
+func TestMarkupParseErrors(t *testing.T) {
+	m, err := NewMarkup()
+	if err != nil {
+		t.Fatalf("NewMarkup error: %v", err)
+	}
+	for _, text := range []string{
+		"{b}unclosed",
+		"{b}{i}crossed{/b}{/i}",
+		"closed{/b}",
+		"{unknown}",
+		"{color=notacolor}x{/color}",
+		"{size=big}x{/size}",
+		"{pause=soon}",
+		"{speed=0}x{/speed}",
+		"{face}",
+		"{img=missing}",
+		"{b=1}x{/b}",
+		"{b",
+	} {
+		if _, err := m.Parse(text); err == nil {
+			t.Errorf("Expected error parsing %q", text)
+		}
+	}
+}
+
+func TestMarkupTextBox(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	m, err := NewMarkup()
+	if err != nil {
+		t.Fatalf("NewMarkup error: %v", err)
+	}
+	m.Images["heart"] = theme.Chevron()
+	args, err := m.Parse("Wait...{pause=1s} it was {color=red}{b}you{/b}{/color}! {img=heart}")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	tb, err := NewRichTextBox(theme, append(args, image.Pt(400, 150))...)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	foundPause, foundImage := false, false
+	for _, l := range tb.pages[0].ls {
+		for _, b := range l.Boxes() {
+			if i, ok := b.(wordwrap.Identifier); ok && i.ID() == Pause(time.Second) {
+				foundPause = true
+			}
+			if _, ok := b.(*wordwrap.ImageBox); ok && b.MetricsRect().Ascent.Ceil() == theme.Chevron().Bounds().Dy() {
+				foundImage = true
+			}
+		}
+	}
+	if !foundPause {
+		t.Errorf("Expected a pause box in the first page")
+	}
+	if !foundImage {
+		t.Errorf("Expected the image to be as tall as the image")
+	}
+	if _, err := NewMarkupTextBox(theme, "{b}Hello{/b}", image.Pt(400, 100)); err != nil {
+		t.Fatalf("Error creating markup text box: %v", err)
+	}
+	i := image.NewRGBA(image.Rect(0, 0, 400, 150))
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+}
+
+// fontSizedTheme reports a font size for a theme
+type fontSizedTheme struct {
+	*spacedTheme
+	size, dpi float64
+}
+
+func (ft *fontSizedTheme) FontSize() float64 {
+	return ft.size
+}
+
+func (ft *fontSizedTheme) FontDPI() float64 {
+	return ft.dpi
+}
+
+func TestNewMarkupForTheme(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	sized := &fontSizedTheme{&spacedTheme{Theme: th, frame: th}, 24, 96}
+	tests := map[string]struct {
+		theme     theme.Theme
+		size, dpi float64
+	}{
+		"Simple":  {th, 16, 75},
+		"Sized":   {sized, 24, 96},
+		"Unsized": {&spacedTheme{Theme: th, frame: th}, 16, 75},
+		"Zero":    {&fontSizedTheme{&spacedTheme{Theme: th, frame: th}, 0, 0}, 16, 75},
+	}
+	for name, wt := range wrappedThemes(t, sized) {
+		tests[name] = struct {
+			theme     theme.Theme
+			size, dpi float64
+		}{wt, 24, 96}
+	}
+	for name, tt := range tests {
+		m, err := NewMarkupForTheme(tt.theme)
+		if err != nil {
+			t.Fatalf("%s: NewMarkupForTheme error: %v", name, err)
+		}
+		if m.Size != tt.size || m.DPI != tt.dpi {
+			t.Errorf("%s: got size %v dpi %v want %v %v", name, m.Size, m.DPI, tt.size, tt.dpi)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package rpgtextbox
+
+import (
+	"image"
+
+	frame "github.com/arran4/golang-frame"
+	"github.com/arran4/golang-rpg-textbox/theme"
+	wordwrap "github.com/arran4/golang-wordwrap"
+	"golang.org/x/image/draw"
+)
+
+// AvatarPanelLayout is an optional extension of Layout for layouts which place the avatar in a panel outside of the
+// frame, without it the panel isn't drawn
+type AvatarPanelLayout interface {
+	// AvatarPanelRect is the optional area containing the frame around an avatar placed outside of FrameRect.
+	AvatarPanelRect() image.Rectangle
+}
+
+// avatarPanelImage is the nine-slice image the avatar panel is drawn with, the theme's AvatarPanel or failing that
+// its Frame. ok is false if the theme has neither.
+func (tb *TextBox) avatarPanelImage() (i image.Image, center image.Rectangle, ok bool) {
+	if ap, ok := tb.theme.(theme.AvatarPanel); ok {
+		if i := ap.AvatarPanel(); i != nil {
+			return i, ap.AvatarPanelCenter(), true
+		}
+	}
+	if f, ok := tb.theme.(theme.Frame); ok {
+		return f.Frame(), f.FrameCenter(), true
+	}
+	return nil, image.Rectangle{}, false
+}
+
+// avatarPanelRects splits destRect into the avatar panel, the avatar inside the panel's borders and what is left for
+// the frame. Panels beside the frame are the full height of destRect and as wide as the avatar fits, panels above
+// the frame are the avatar's size.
+func (tb *TextBox) avatarPanelRects(destRect image.Rectangle, spacing theme.Spacing) (panel, avatar, rest image.Rectangle) {
+	var topLeft, bottomRight image.Point
+	if pi, pc, ok := tb.avatarPanelImage(); ok {
+		topLeft, bottomRight = pc.Min.Sub(pi.Bounds().Min), pi.Bounds().Max.Sub(pc.Max)
+	}
+	border := topLeft.Add(bottomRight)
+	size := tb.Avatar().Bounds().Size()
+	rest = destRect
+	switch tb.avatarLocation {
+	case LeftAvatarPanel, RightAvatarPanel:
+		size = tb.avatarSlot(image.Pt(destRect.Dx()-border.X, destRect.Dy()-border.Y))
+		width := min(size.X+border.X, destRect.Dx())
+		if tb.avatarLocation == LeftAvatarPanel {
+			panel = image.Rect(destRect.Min.X, destRect.Min.Y, destRect.Min.X+width, destRect.Max.Y)
+			rest.Min.X = panel.Max.X + spacing.AvatarGutter
+		} else {
+			panel = image.Rect(destRect.Max.X-width, destRect.Min.Y, destRect.Max.X, destRect.Max.Y)
+			rest.Max.X = panel.Min.X - spacing.AvatarGutter
+		}
+	case TopLeftAvatarPanel, TopRightAvatarPanel:
+		width, height := min(size.X+border.X, destRect.Dx()), min(size.Y+border.Y, destRect.Dy())
+		x := destRect.Min.X
+		if tb.avatarLocation == TopRightAvatarPanel {
+			x = destRect.Max.X - width
+		}
+		panel = image.Rect(x, destRect.Min.Y, x+width, destRect.Min.Y+height)
+		rest.Min.Y = panel.Max.Y + spacing.AvatarGutter
+	}
+	avatar = image.Rectangle{Min: panel.Min.Add(topLeft), Max: panel.Max.Sub(bottomRight)}
+	return panel, avatar, rest
+}
+
+// drawAvatarPanel draws the frame around an avatar placed outside the main frame
+func (tb *TextBox) drawAvatarPanel(target wordwrap.Image, layout Layout, options ...wordwrap.DrawOption) {
+	apl, ok := layout.(AvatarPanelLayout)
+	if !ok {
+		return
+	}
+	pr := apl.AvatarPanelRect()
+	pi, pc, ok := tb.avatarPanelImage()
+	if pr.Empty() || !ok {
+		return
+	}
+	for _, option := range options {
+		switch option := option.(type) {
+		case wordwrap.SourceImageMapper:
+			pi = option(pi)
+		}
+	}
+	fd := frame.NewFrame(pr, pi, pc, frame.Stretched)
+	draw.Draw(target.SubImage(pr).(wordwrap.Image), pr, fd, fd.Bounds().Min, draw.Over)
+}
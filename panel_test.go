@@ -0,0 +1,174 @@
+package rpgtextbox
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	"golang.org/x/image/draw"
+)
+
+// avatarPanelTheme adds an avatar panel to a theme
+type avatarPanelTheme struct {
+	*spacedTheme
+	panel image.Image
+}
+
+func (at *avatarPanelTheme) AvatarPanel() image.Image {
+	return at.panel
+}
+
+func (at *avatarPanelTheme) AvatarPanelCenter() image.Rectangle {
+	return image.Rect(3, 3, 9, 9)
+}
+
+func TestAvatarPanel(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	panel := image.NewUniform(color.RGBA{G: 255, A: 255})
+	pi := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	draw.Draw(pi, pi.Bounds(), panel, image.Point{}, draw.Src)
+	at := &avatarPanelTheme{&spacedTheme{Theme: th, frame: th, spacing: theme.Spacing{AvatarGutter: 5}}, pi}
+	bounds := image.Rect(0, 0, 600, 300)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box. " +
+		"It goes on and on for a while longer so that there is more than one page."
+	for _, location := range []AvatarLocations{LeftAvatarPanel, RightAvatarPanel, TopLeftAvatarPanel, TopRightAvatarPanel} {
+		name := fmt.Sprintf("Location%d", location)
+		tb, err := NewSimpleTextBox(at, text, bounds.Size(), location, Name("Panel"), NameTopLeftAboveFrame, RightBottomOnFrameFrame)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		l, err := NewSimpleLayout(tb, bounds)
+		if err != nil {
+			t.Fatalf("%s: Error creating layout: %v", name, err)
+		}
+		pr, ar, fr := l.AvatarPanelRect(), l.AvatarRect(), l.FrameRect()
+		if !pr.In(bounds) || pr.Empty() {
+			t.Fatalf("%s: panel %v outside of %v", name, pr, bounds)
+		}
+		if ar.Min.Sub(pr.Min) != image.Pt(3, 3) || pr.Max.Sub(ar.Max) != image.Pt(3, 3) {
+			t.Errorf("%s: avatar %v not inside the panel's center %v", name, ar, pr)
+		}
+		want := tb.Avatar().Bounds().Size()
+		if location == LeftAvatarPanel || location == RightAvatarPanel {
+			// Beside the frame the panel is the full height
+			want.Y = bounds.Dy() - 6
+		}
+		if ar.Size() != want {
+			t.Errorf("%s: avatar %v want size %v", name, ar, want)
+		}
+		for _, r := range []image.Rectangle{fr, l.NameRect(), l.ChevronRect()} {
+			if r.Overlaps(pr) {
+				t.Errorf("%s: %v overlaps the panel %v", name, r, pr)
+			}
+		}
+		var gap int
+		switch location {
+		case LeftAvatarPanel:
+			gap = fr.Min.X - pr.Max.X
+		case RightAvatarPanel:
+			gap = pr.Min.X - fr.Max.X
+		case TopLeftAvatarPanel, TopRightAvatarPanel:
+			gap = l.NameRect().Min.Y - pr.Max.Y
+		}
+		if gap != 5 {
+			t.Errorf("%s: %d pixels between the panel %v and the frame %v want 5", name, gap, pr, fr)
+		}
+		if l.ChevronRect().Max.X > fr.Max.X || l.ChevronRect().Min.X < fr.Min.X {
+			t.Errorf("%s: chevron %v outside of the frame %v", name, l.ChevronRect(), fr)
+		}
+		sr := &shapeRecorder{}
+		tb.SetSpaceMap(sr)
+		i := image.NewRGBA(bounds)
+		if _, err := tb.DrawNextPageFrame(i); err != nil {
+			t.Fatalf("%s: Draw next frame error: %v", name, err)
+		}
+		if c := i.At(pr.Min.X+1, pr.Min.Y+1); c != panel.C {
+			t.Errorf("%s: expected the panel to be drawn at %v got %v", name, pr.Min, c)
+		}
+		if len(*sr) == 0 {
+			t.Errorf("%s: expected boxes in the space map", name)
+		}
+		for _, s := range *sr {
+			if bs, ok := s.(*BoxShape); ok && !bs.Rect.In(l.TextRect()) {
+				t.Errorf("%s: box %v outside of the text %v", name, bs.Rect, l.TextRect())
+			}
+		}
+	}
+}
+
+func TestAvatarPanelWrapped(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	panel := image.NewUniform(color.RGBA{G: 255, A: 255})
+	pi := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	draw.Draw(pi, pi.Bounds(), panel, image.Point{}, draw.Src)
+	at := &avatarPanelTheme{&spacedTheme{Theme: th, frame: th}, pi}
+	bounds := image.Rect(0, 0, 600, 300)
+	for name, wt := range wrappedThemes(t, at) {
+		tb, err := NewSimpleTextBox(wt, "Some text", bounds.Size(), LeftAvatarPanel)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		l, err := NewSimpleLayout(tb, bounds)
+		if err != nil {
+			t.Fatalf("%s: Error creating layout: %v", name, err)
+		}
+		i := image.NewRGBA(bounds)
+		if _, err := tb.DrawNextPageFrame(i); err != nil {
+			t.Fatalf("%s: Draw next frame error: %v", name, err)
+		}
+		if pr := l.AvatarPanelRect(); i.At(pr.Min.X+1, pr.Min.Y+1) != panel.C {
+			t.Errorf("%s: expected the panel to be drawn at %v", name, pr.Min)
+		}
+	}
+	for name, wt := range wrappedThemes(t, th) {
+		tb := &TextBox{theme: wt}
+		if i, _, ok := tb.avatarPanelImage(); !ok || i != th.Frame() {
+			t.Errorf("%s: Expected the frame for the avatar panel without one", name)
+		}
+	}
+}
+
+// baseLayout hides everything but the Layout methods of the layout it wraps
+type baseLayout struct {
+	Layout
+}
+
+func TestAvatarPanelBaseLayout(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	panel := image.NewUniform(color.RGBA{G: 255, A: 255})
+	pi := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	draw.Draw(pi, pi.Bounds(), panel, image.Point{}, draw.Src)
+	at := &avatarPanelTheme{&spacedTheme{Theme: th, frame: th}, pi}
+	bounds := image.Rect(0, 0, 600, 300)
+	var pr image.Rectangle
+	tb, err := NewSimpleTextBox(at, "Some text", bounds.Size(), LeftAvatarPanel, LayoutFactory(func(tb *TextBox, destRect image.Rectangle) (Layout, error) {
+		sl, err := NewSimpleLayout(tb, destRect)
+		if err != nil {
+			return nil, err
+		}
+		pr = sl.AvatarPanelRect()
+		return &baseLayout{sl}, nil
+	}))
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(bounds)
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if i.At(pr.Min.X+1, pr.Min.Y+1) == panel.C {
+		t.Errorf("Expected no panel for a layout without AvatarPanelRect")
+	}
+}
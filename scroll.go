@@ -0,0 +1,188 @@
+package rpgtextbox
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arran4/golang-wordwrap"
+)
+
+// scrollAnimationName identifies the ScrollAnimation in an AnimationState
+const scrollAnimationName = "scroll"
+
+// ScrollAnimation is an animation style in which lines are added to the bottom of the text one at a time, pushing the
+// lines already shown up like a log rather than clearing the page. After each line it either waits for user input or,
+// if LineWaitTime is set, continues by itself. It always waits for user input after the last line.
+type ScrollAnimation struct {
+	tb *TextBox
+	// lineNumber is the line of the page being scrolled in
+	lineNumber int
+	// scroll is how many pixels the line being scrolled in has moved so far
+	scroll int
+	layout Layout
+	page   *Page
+	// skip is set by Skip to finish scrolling in the page in the next frame
+	skip bool
+	// ScrollStep is how many pixels the text moves each frame, 0 or less moves a whole line at once
+	ScrollStep int
+	// ScrollWaitTime is the time between each ScrollStep
+	ScrollWaitTime time.Duration
+	// LineWaitTime if positive is the time to wait after a line has scrolled in before starting the next, otherwise it
+	// waits for user input
+	LineWaitTime time.Duration
+}
+
+// DrawOption draws with options.. Controls the drawing process to add extra frames, a wait time and more
+// finished is true if you're on the last page
+// userInputAccepted is if it's at the stage where you would typically accept user input (ie the animation is waiting
+// user input, doesn't imply anything to do with the animation
+// wait is either 0 or less, or the amount of time before the next animation phase
+// err is err
+// To determine if you're at the end the only way of doing it as of writing is to wait for; lastPage = true,
+// userInputAccepted = false, wait = -1
+func (sa *ScrollAnimation) DrawOption(target wordwrap.Image) (finished bool, userInputAccepted bool, waitTime time.Duration, err error) {
+	if sa.layout == nil {
+		sa.layout, sa.page, err = sa.tb.animationPage(target.Bounds(), sa.page)
+		if err != nil {
+			return
+		}
+		if sa.layout == nil || sa.page == nil {
+			finished = true
+			waitTime = -1
+			return
+		}
+	}
+	if sa.lineNumber >= len(sa.page.ls) {
+		sa.reset()
+		return sa.DrawOption(target)
+	}
+	skip := sa.skip
+	if skip {
+		sa.lineNumber = max(sa.lineNumber, len(sa.page.ls)-1)
+	}
+	height := sa.layout.TextRect().Dy()
+	ls := sa.tb.linesBefore(sa.tb.nextPage-1, sa.lineNumber, height)
+	shown := 0
+	for _, l := range ls {
+		shown += l.Size().Dy()
+	}
+	line := sa.page.ls[sa.lineNumber]
+	// The text moves from where the lines shown so far are aligned to where they are with the new line
+	from := sa.tb.verticalOffset(shown, height)
+	distance := from - sa.tb.verticalOffset(shown+line.Size().Dy(), height)
+	if sa.ScrollStep > 0 && !sa.skip {
+		sa.scroll = min(sa.scroll+sa.ScrollStep, distance)
+	} else {
+		sa.scroll = distance
+	}
+	sa.skip = false
+	complete := sa.scroll == distance
+	more := sa.lineNumber+1 < len(sa.page.ls) || sa.tb.HasNext()
+	wait := complete && (!more || sa.LineWaitTime <= 0 || skip)
+	done, err := sa.tb.drawLines(target, sa.layout, append(ls, line), sa.scroll-distance, wait && more, more)
+	if err != nil {
+		return
+	}
+	switch {
+	case !complete:
+		waitTime = sa.ScrollWaitTime
+		return
+	case wait:
+		finished = done && !more
+		userInputAccepted = true
+		waitTime = -1
+	default:
+		waitTime = sa.LineWaitTime
+	}
+	sa.scroll = 0
+	sa.lineNumber++
+	return
+}
+
+// Skip finishes scrolling in the rest of the page, it does nothing once the page is complete
+func (sa *ScrollAnimation) Skip() {
+	if sa.page == nil {
+		// Before the first page is fetched the skip applies to it
+		sa.skip = sa.tb.nextPage == 0
+		return
+	}
+	sa.skip = sa.lineNumber < len(sa.page.ls)
+}
+
+// reset returns the animation to the start of a page
+func (sa *ScrollAnimation) reset() {
+	sa.lineNumber = 0
+	sa.scroll = 0
+	sa.skip = false
+	sa.layout = nil
+	sa.page = nil
+}
+
+// snapshot captures the animation's position for State
+func (sa *ScrollAnimation) snapshot() *AnimationState {
+	return &AnimationState{
+		Type:       scrollAnimationName,
+		InPage:     sa.page != nil,
+		LineNumber: sa.lineNumber,
+		Scroll:     sa.scroll,
+	}
+}
+
+// restore returns the animation to a position captured by snapshot
+func (sa *ScrollAnimation) restore(as *AnimationState) error {
+	if as.Type != scrollAnimationName {
+		return fmt.Errorf("can not restore a %s animation state into a %s animation", as.Type, scrollAnimationName)
+	}
+	page, err := sa.tb.animationStatePage(as)
+	if err != nil {
+		return err
+	}
+	if page != nil && as.LineNumber > len(page.ls) {
+		return fmt.Errorf("line %d out of range, page has %d lines", as.LineNumber, len(page.ls))
+	}
+	sa.reset()
+	sa.page = page
+	sa.lineNumber = as.LineNumber
+	sa.scroll = as.Scroll
+	return nil
+}
+
+// apply Set the location when used as an Option
+func (sa *ScrollAnimation) apply(box *TextBox) {
+	sa.tb = box
+	box.animation = sa
+}
+
+// Enforce the interface
+var _ AnimationMode = (*ScrollAnimation)(nil)
+
+// NewScrollAnimation creates an animation style where each line scrolls in from the bottom a few pixels at a time and
+// then waits for user input. Set ScrollStep to 0 to add whole lines and LineWaitTime to continue automatically
+func NewScrollAnimation() *ScrollAnimation {
+	return &ScrollAnimation{
+		ScrollStep:     4,
+		ScrollWaitTime: time.Second / 30,
+	}
+}
+
+// linesBefore returns the lines that come before line n of page p, oldest first, going back through earlier pages until
+// there are enough to fill height pixels
+func (tb *TextBox) linesBefore(p, n, height int) []wordwrap.Line {
+	var ls []wordwrap.Line
+	total := 0
+	for ; p >= 0 && total < height; p-- {
+		pls := tb.pages[p].ls
+		if n < 0 {
+			n = len(pls)
+		}
+		for n--; n >= 0 && total < height; n-- {
+			ls = append(ls, pls[n])
+			total += pls[n].Size().Dy()
+		}
+		n = -1
+	}
+	for i, j := 0, len(ls)-1; i < j; i, j = i+1, j-1 {
+		ls[i], ls[j] = ls[j], ls[i]
+	}
+	return ls
+}
@@ -0,0 +1,155 @@
+package rpgtextbox
+
+import (
+	"bytes"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+)
+
+func TestScrollAnimation(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(300, 100)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box."
+	static, err := NewSimpleTextBox(theme, text, size)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	if _, err := static.CalculateAllPages(size); err != nil {
+		t.Fatalf("Error calculating pages: %v", err)
+	}
+	lines := 0
+	for _, p := range static.pages {
+		lines += len(p.ls)
+	}
+	tests := []struct {
+		name         string
+		lineWaitTime time.Duration
+		wantInputs   int
+	}{
+		{"WaitForInput", 0, lines},
+		{"Continue", time.Second, 1},
+	}
+	for _, tt := range tests {
+		a := NewScrollAnimation()
+		a.LineWaitTime = tt.lineWaitTime
+		tb, err := NewSimpleTextBox(theme, text, size, a)
+		if err != nil {
+			t.Fatalf("Error creating text box: %v", err)
+		}
+		inputs := 0
+		scrolled := false
+		for f := 0; ; f++ {
+			if f > 1000 {
+				t.Fatalf("%s: animation never finished", tt.name)
+			}
+			lastPage, ui, wait, err := tb.DrawNextFrame(image.NewRGBA(image.Rect(0, 0, size.X, size.Y)))
+			if err != nil {
+				t.Fatalf("Draw next frame error: %v", err)
+			}
+			if lastPage && !ui && wait < 0 {
+				break
+			}
+			if ui {
+				inputs++
+			}
+			if wait == a.ScrollWaitTime {
+				scrolled = true
+			}
+		}
+		if inputs != tt.wantInputs {
+			t.Errorf("%s: accepted user input %d times want %d", tt.name, inputs, tt.wantInputs)
+		}
+		if !scrolled {
+			t.Errorf("%s: expected lines to scroll in over several frames", tt.name)
+		}
+	}
+}
+
+func TestScrollAnimationState(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(300, 100)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box."
+	tb, err := NewSimpleTextBox(theme, text, size, NewScrollAnimation())
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	for f := 0; f < 6; f++ {
+		if _, _, _, err := tb.DrawNextFrame(image.NewRGBA(image.Rect(0, 0, size.X, size.Y))); err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+	}
+	restored, err := NewSimpleTextBox(theme, text, size, NewScrollAnimation())
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	if err := restored.Restore(tb.Snapshot()); err != nil {
+		t.Fatalf("Restore error: %v", err)
+	}
+	want := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	got := image.NewRGBA(want.Rect)
+	if _, _, _, err := tb.DrawNextFrame(want); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if _, _, _, err := restored.DrawNextFrame(got); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if !bytes.Equal(want.Pix, got.Pix) {
+		t.Errorf("Expected the restored text box to draw the same frame")
+	}
+}
+
+func TestScrollAnimationSkip(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	size := image.Pt(400, 150)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box. " +
+		"It goes on and on for a while longer so that there is more than one page."
+	want := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	static, err := NewSimpleTextBox(theme, text, size)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	if _, err := static.DrawNextPageFrame(want); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if len(static.pages[0].ls) < 2 {
+		t.Fatalf("Expected the first page to have several lines got %d", len(static.pages[0].ls))
+	}
+	a := NewScrollAnimation()
+	a.LineWaitTime = time.Second
+	tb, err := NewSimpleTextBox(theme, text, size, a)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	for f := 0; f < 2; f++ {
+		if _, ui, _, err := tb.DrawNextFrame(image.NewRGBA(want.Rect)); err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		} else if ui {
+			t.Fatalf("User input accepted before skipping")
+		}
+	}
+	tb.Skip()
+	got := image.NewRGBA(want.Rect)
+	if _, ui, _, err := tb.DrawNextFrame(got); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	} else if !ui {
+		t.Errorf("Expected user input to be accepted after Skip")
+	}
+	if !bytes.Equal(want.Pix, got.Pix) {
+		t.Errorf("Expected the whole page to be drawn after Skip")
+	}
+	if tb.CurrentPage() != 0 {
+		t.Errorf("CurrentPage() = %d want 0", tb.CurrentPage())
+	}
+}
@@ -0,0 +1,140 @@
+package rpgtextbox
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// StateVersion is the version of State produced by Snapshot, Restore will refuse other versions
+const StateVersion = 2
+
+// State is a JSON encodable record of how far through a TextBox the reader is. It is intended to be saved (ie in a save
+// game) and restored against a TextBox constructed with the same theme, text and options.
+type State struct {
+	// Version is the StateVersion the state was created with
+	Version int `json:"version"`
+	// NextPage is the page the next draw call will render
+	NextPage int `json:"nextPage"`
+	// PageRects are the text rectangles each calculated page was wrapped into, in order. Used to bring the word wrapper
+	// to the same position
+	PageRects []image.Rectangle `json:"pageRects"`
+	// Animation is the state of the animation if there is one
+	Animation *AnimationState `json:"animation,omitempty"`
+	// ChevronWait is the state of the animated chevron if the text box is waiting for user input
+	ChevronWait *ChevronWaitState `json:"chevronWait,omitempty"`
+}
+
+// ChevronWaitState is the position of the animated chevron while the text box waits for user input
+type ChevronWaitState struct {
+	// Frame is the index of the chevron frame drawn last
+	Frame int `json:"frame"`
+	// LastPage is what DrawNextFrame returned when the wait started
+	LastPage bool `json:"lastPage"`
+}
+
+// AnimationState is the position of an AnimationMode within the current page
+type AnimationState struct {
+	// Type is the name of the animation the state came from
+	Type string `json:"type"`
+	// InPage is true if the animation is part way through the most recently fetched page
+	InPage bool `json:"inPage"`
+	// FadeState is the FadeAnimation direction
+	FadeState FadeState `json:"fadeState,omitempty"`
+	// Step is the FadeAnimation step
+	Step int `json:"step,omitempty"`
+	// BoxNumber is the BoxByBoxAnimation or LetterByLetterAnimation box
+	BoxNumber int `json:"boxNumber,omitempty"`
+	// LetterNumber is the LetterByLetterAnimation letter (grapheme cluster) within the box
+	LetterNumber int `json:"letterNumber,omitempty"`
+	// LineNumber is the ScrollAnimation line being scrolled in
+	LineNumber int `json:"lineNumber,omitempty"`
+	// Scroll is how far in pixels the ScrollAnimation line has scrolled in
+	Scroll int `json:"scroll,omitempty"`
+}
+
+// Snapshot captures the current progress of the TextBox so that it can be restored with Restore
+func (tb *TextBox) Snapshot() *State {
+	s := &State{
+		Version:   StateVersion,
+		NextPage:  tb.nextPage,
+		PageRects: make([]image.Rectangle, 0, len(tb.pages)),
+	}
+	for _, p := range tb.pages {
+		s.PageRects = append(s.PageRects, p.rect)
+	}
+	if tb.animation != nil {
+		s.Animation = tb.animation.snapshot()
+	}
+	if tb.chevronWait != nil {
+		s.ChevronWait = &ChevronWaitState{
+			Frame:    tb.chevronWait.frame,
+			LastPage: tb.chevronWait.lastPage,
+		}
+	}
+	return s
+}
+
+// Restore returns the TextBox to the progress captured by Snapshot. The TextBox must have been created with the same
+// theme, text and options as the one the State was taken from.
+func (tb *TextBox) Restore(s *State) error {
+	if s == nil {
+		return errors.New("no state to restore")
+	}
+	if s.Version != StateVersion {
+		return fmt.Errorf("unsupported state version %d", s.Version)
+	}
+	if s.NextPage < 0 || s.NextPage > len(s.PageRects) {
+		return fmt.Errorf("next page %d out of range, %d pages in state", s.NextPage, len(s.PageRects))
+	}
+	for i, r := range s.PageRects {
+		if i < len(tb.pages) {
+			if tb.pages[i].rect != r {
+				return fmt.Errorf("page %d was calculated at %v but state has %v", i, tb.pages[i].rect, r)
+			}
+			continue
+		}
+		found, err := tb.calculateNextFrame(&SimpleLayout{textRect: r})
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("state has %d pages but the text only has %d", len(s.PageRects), len(tb.pages))
+		}
+	}
+	if cw := s.ChevronWait; cw != nil && (s.NextPage < 1 || cw.Frame < 0) {
+		return fmt.Errorf("chevron wait at frame %d before page %d out of range", cw.Frame, s.NextPage-1)
+	}
+	tb.nextPage = s.NextPage
+	tb.chevronWait = nil
+	tb.shown = nil
+	switch {
+	case tb.animation == nil && s.Animation != nil:
+		return fmt.Errorf("state has a %s animation but the text box has none", s.Animation.Type)
+	case tb.animation == nil:
+	case s.Animation == nil:
+		tb.animation.reset()
+	default:
+		if err := tb.animation.restore(s.Animation); err != nil {
+			return err
+		}
+	}
+	if s.ChevronWait != nil {
+		tb.chevronWait = &chevronWait{
+			frame:    s.ChevronWait.Frame,
+			lastPage: s.ChevronWait.LastPage,
+		}
+	}
+	return nil
+}
+
+// animationStatePage returns the page a restored animation is part way through
+func (tb *TextBox) animationStatePage(as *AnimationState) (*Page, error) {
+	if !as.InPage {
+		return nil, nil
+	}
+	if tb.nextPage < 1 || tb.nextPage > len(tb.pages) {
+		return nil, fmt.Errorf("%s animation is part way through a page but page %d has not been drawn", as.Type, tb.nextPage-1)
+	}
+	return tb.pages[tb.nextPage-1], nil
+}
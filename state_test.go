@@ -0,0 +1,102 @@
+package rpgtextbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	textBoxSize := image.Pt(300, 100)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box."
+	tests := []struct {
+		name      string
+		animation func() Option
+		frames    int
+	}{
+		{"NoAnimation", func() Option { return NoMoreChevron }, 2},
+		{"FadeAnimation", func() Option { return NewFadeAnimation() }, 30},
+		{"BoxByBoxAnimation", func() Option { return NewBoxByBoxAnimation() }, 25},
+		{"LetterByLetterAnimation", func() Option { return NewLetterByLetterAnimation() }, 70},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, err := NewSimpleTextBox(theme, text, textBoxSize, LeftAvatar, tt.animation())
+			if err != nil {
+				t.Fatalf("Error creating text box: %v", err)
+			}
+			for f := 0; f < tt.frames; f++ {
+				if _, _, _, err := original.DrawNextFrame(image.NewRGBA(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y))); err != nil {
+					t.Fatalf("Draw next frame error: %v", err)
+				}
+			}
+			b, err := json.Marshal(original.Snapshot())
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			state := &State{}
+			if err := json.Unmarshal(b, state); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			restored, err := NewSimpleTextBox(theme, text, textBoxSize, LeftAvatar, tt.animation())
+			if err != nil {
+				t.Fatalf("Error creating text box: %v", err)
+			}
+			if err := restored.Restore(state); err != nil {
+				t.Fatalf("Restore error: %v", err)
+			}
+			for f := 0; f < 5; f++ {
+				want := image.NewRGBA(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y))
+				got := image.NewRGBA(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y))
+				wl, wui, ww, err := original.DrawNextFrame(want)
+				if err != nil {
+					t.Fatalf("Draw next frame error: %v", err)
+				}
+				gl, gui, gw, err := restored.DrawNextFrame(got)
+				if err != nil {
+					t.Fatalf("Draw next frame error: %v", err)
+				}
+				if wl != gl || wui != gui || ww != gw {
+					t.Errorf("frame %d: got (%v, %v, %v) want (%v, %v, %v)", f, gl, gui, gw, wl, wui, ww)
+				}
+				if !bytes.Equal(want.Pix, got.Pix) {
+					t.Errorf("frame %d: restored text box rendered differently", f)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreMismatch(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	textBoxSize := image.Pt(300, 100)
+	tb, err := NewSimpleTextBox(theme, "Short text", textBoxSize, NewFadeAnimation())
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	state := tb.Snapshot()
+	state.Version = StateVersion + 1
+	if err := tb.Restore(state); err == nil {
+		t.Errorf("Expected error restoring an unknown version")
+	}
+	state = tb.Snapshot()
+	state.PageRects = append(state.PageRects, state.PageRects[0])
+	if err := tb.Restore(state); err == nil {
+		t.Errorf("Expected error restoring more pages than the text has")
+	}
+	state = tb.Snapshot()
+	state.Animation.Type = letterByLetterAnimationName
+	if err := tb.Restore(state); err == nil {
+		t.Errorf("Expected error restoring a different animation")
+	}
+}
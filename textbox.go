@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"image"
 	"log"
+	"strings"
 	"time"
 
 	frame "github.com/arran4/golang-frame"
@@ -25,8 +26,25 @@ const (
 	LeftAvatar
 	// RightAvatar positions the avatar on the right.
 	RightAvatar
+	// LeftAvatarPanel positions the avatar in its own panel left of the frame.
+	LeftAvatarPanel
+	// RightAvatarPanel positions the avatar in its own panel right of the frame.
+	RightAvatarPanel
+	// TopLeftAvatarPanel positions the avatar in its own panel above the frame on the left.
+	TopLeftAvatarPanel
+	// TopRightAvatarPanel positions the avatar in its own panel above the frame on the right.
+	TopRightAvatarPanel
 )
 
+// outside returns true if the avatar is drawn in a panel outside of the frame
+func (al AvatarLocations) outside() bool {
+	switch al {
+	case LeftAvatarPanel, RightAvatarPanel, TopLeftAvatarPanel, TopRightAvatarPanel:
+		return true
+	}
+	return false
+}
+
 // apply implements the Option interface.
 func (al AvatarLocations) apply(box *TextBox) {
 	box.avatarLocation = al
@@ -48,6 +66,12 @@ const (
 	NameTopLeftAboveFrame
 	// NameTopCenterAboveFrame positions the name tag above the frame, centered.
 	NameTopCenterAboveFrame
+	// NameTopLeftOnFrame positions the name tag over the frame's top border, aligned left.
+	NameTopLeftOnFrame
+	// NameTopCenterOnFrame positions the name tag over the frame's top border, centered.
+	NameTopCenterOnFrame
+	// NameTopRightOnFrame positions the name tag over the frame's top border, aligned right.
+	NameTopRightOnFrame
 )
 
 // apply implements the Option interface.
@@ -98,6 +122,15 @@ const (
 	NearestNeighbour
 	// ApproxBiLinear scales using approximate bi-linear interpolation.
 	ApproxBiLinear
+	// Contain scales the avatar up or down to the largest size that fits, keeping its aspect ratio.
+	Contain
+	// Cover scales the avatar up or down to fill the space, keeping its aspect ratio and cropping what doesn't fit.
+	Cover
+	// IntegerScale scales the avatar by the largest whole number that fits using nearest-neighbor, keeping pixel art
+	// crisp.
+	IntegerScale
+	// CatmullRom scales using Catmull-Rom interpolation, higher quality than ApproxBiLinear but slower.
+	CatmullRom
 )
 
 // apply implements the Option interface.
@@ -117,8 +150,8 @@ type boxTextBox struct{}
 var _ PostDrawer = (*boxTextBox)(nil)
 
 // PostDraw applies the PostDrawer interface and will execute after all the other elements have been drawn
-func (btb *boxTextBox) PostDraw(target wordwrap.Image, layout *SimpleLayout, ls []wordwrap.Line, options ...wordwrap.DrawOption) error {
-	util.DrawBox(target, layout.textRect)
+func (btb *boxTextBox) PostDraw(target wordwrap.Image, layout Layout, ls []wordwrap.Line, options ...wordwrap.DrawOption) error {
+	util.DrawBox(target, layout.TextRect())
 	return nil
 }
 
@@ -129,7 +162,7 @@ func (btb *boxTextBox) apply(box *TextBox) {
 
 // PostDrawer allows custom components to be drawn after standard elements.
 type PostDrawer interface {
-	PostDraw(target wordwrap.Image, layout *SimpleLayout, ls []wordwrap.Line, options ...wordwrap.DrawOption) error
+	PostDraw(target wordwrap.Image, layout Layout, ls []wordwrap.Line, options ...wordwrap.DrawOption) error
 }
 
 // TextBox is the main component for rendering RPG-style text boxes.
@@ -143,12 +176,26 @@ type TextBox struct {
 	nextPage            int
 	pages               []*Page
 	avatarFit           AvatarFit
+	avatarAnchor        AvatarAnchor
 	avatar              *avatar
 	postDraw            []PostDrawer
 	animation           AnimationMode
 	namePosition        NamePositions
 	nameBox             wordwrap.Box
 	spaceMap            SpaceMap
+	choiceMenu          *ChoiceMenu
+	speechBubble        *SpeechBubble
+	layoutFactory       LayoutFactory
+	shrinkToFit         *ShrinkToFit
+	autoSize            *AutoSize
+	horizontalAlignment HorizontalTextAlignment
+	verticalAlignment   VerticalTextAlignment
+	spacing             *theme.Spacing
+	shown               *shownLines
+	chevronWait         *chevronWait
+	avatarProvider      AvatarProvider
+	face                Face
+	talkFrame           int
 }
 
 // SpaceMap is an interface for mapping screen space to interactive shapes.
@@ -192,6 +239,16 @@ func (a *avatar) apply(box *TextBox) {
 	box.avatar = a
 }
 
+// Spacing sets the space in pixels between the frame, avatar, name, chevron and text, replacing the theme's
+// theme.Spaced default.
+type Spacing theme.Spacing
+
+// apply Set the spacing when used as an Option
+func (s Spacing) apply(box *TextBox) {
+	ts := theme.Spacing(s)
+	box.spacing = &ts
+}
+
 type wordwrapOption struct {
 	opt wordwrap.WrapperOption
 }
@@ -217,9 +274,12 @@ func NewSimpleTextBox(th theme.Theme, text string, destSize image.Point, options
 }
 
 // NewRichTextBox creates a TextBox with rich text content (e.g. colors, images).
-// theme is required.
+// theme is required and must have the images the text box uses, if it doesn't the error says why (see checkTheme).
 // args can include string content, image.Point (for size), and Options.
 func NewRichTextBox(th theme.Theme, args ...interface{}) (*TextBox, error) {
+	if th == nil {
+		return nil, fmt.Errorf("invalid theme: %w", theme.ErrNoTheme)
+	}
 	tb := &TextBox{
 		theme: th,
 	}
@@ -245,7 +305,11 @@ func NewRichTextBox(th theme.Theme, args ...interface{}) (*TextBox, error) {
 		}
 	}
 
-	if !foundDestSize {
+	if err := tb.checkTheme(); err != nil {
+		return nil, fmt.Errorf("invalid theme: %w", err)
+	}
+
+	if !foundDestSize && tb.autoSize == nil {
 		log.Printf("Warning: destSize not found in NewRichTextBox arguments")
 	}
 
@@ -276,17 +340,28 @@ func NewRichTextBox(th theme.Theme, args ...interface{}) (*TextBox, error) {
 		wordwrapArgs = append(wordwrapArgs, opt)
 	}
 
-	if tb.wrapper == nil {
-		tb.wrapper = wordwrap.NewRichWrapper(wordwrapArgs...)
+	if tb.autoSize != nil {
+		var err error
+		if destSize, err = tb.autoSize.measure(tb, wordwrapArgs); err != nil {
+			return nil, err
+		}
 	}
 	destRect := image.Rectangle{
 		Min: image.Point{},
 		Max: destSize,
 	}
-	layout, err := NewSimpleLayout(tb, destRect)
+	layout, err := tb.newLayout(destRect)
 	if err != nil {
 		return nil, err
 	}
+	if tb.wrapper == nil && tb.shrinkToFit != nil {
+		if tb.wrapper, err = tb.shrinkToFit.wrapper(th, layout, wordwrapArgs); err != nil {
+			return nil, err
+		}
+	}
+	if tb.wrapper == nil {
+		tb.wrapper = wordwrap.NewRichWrapper(wordwrapArgs...)
+	}
 	found, err := tb.calculateNextFrame(layout)
 	if err != nil {
 		return nil, err
@@ -313,6 +388,7 @@ func (tb *TextBox) calculateNextFrame(layout Layout) (bool, error) {
 	page := &Page{
 		ls:       ls,
 		boxCount: boxCount,
+		rect:     layout.TextRect(),
 	}
 	tb.pages = append(tb.pages, page)
 	return true, nil
@@ -334,29 +410,76 @@ type Layout interface {
 	FrameRect() image.Rectangle
 }
 
+// NamePlateLayout is an optional extension of Layout for layouts with an area for the theme's name plate, without it
+// the name plate isn't drawn
+type NamePlateLayout interface {
+	// NamePlateRect is the optional area containing the name tag's plate, the same as NameRect if the theme has no
+	// theme.NamePlate.
+	NamePlateRect() image.Rectangle
+}
+
+// LayoutFactory creates the Layout for a text box drawn into destRect. Used as an Option to replace NewSimpleLayout
+// with your own layout, it is called for every page.
+type LayoutFactory func(tb *TextBox, destRect image.Rectangle) (Layout, error)
+
+// apply Set the layout factory when used as an Option
+func (lf LayoutFactory) apply(box *TextBox) {
+	box.layoutFactory = lf
+}
+
+// newLayout creates the layout for destRect using the LayoutFactory if there is one
+func (tb *TextBox) newLayout(destRect image.Rectangle) (Layout, error) {
+	if tb.layoutFactory != nil {
+		return tb.layoutFactory(tb, destRect)
+	}
+	l, err := NewSimpleLayout(tb, destRect)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
 // SimpleLayout implements a standard text box layout.
 type SimpleLayout struct {
-	textRect    image.Rectangle
-	centerRect  image.Rectangle
-	avatarRect  image.Rectangle
-	chevronRect image.Rectangle
-	nameRect    image.Rectangle
-	frameRect   image.Rectangle
+	textRect      image.Rectangle
+	centerRect    image.Rectangle
+	avatarRect    image.Rectangle
+	chevronRect   image.Rectangle
+	nameRect      image.Rectangle
+	namePlateRect image.Rectangle
+	frameRect     image.Rectangle
+	panelRect     image.Rectangle
+	choiceRect    image.Rectangle
+	tailRect      image.Rectangle
 }
 
 // Interface enforcement
 var _ Layout = (*SimpleLayout)(nil)
+var _ NamePlateLayout = (*SimpleLayout)(nil)
+var _ AvatarPanelLayout = (*SimpleLayout)(nil)
+var _ ChoiceLayout = (*SimpleLayout)(nil)
+var _ TailLayout = (*SimpleLayout)(nil)
 
 // NameRect returns the name tag rectangle.
 func (sl *SimpleLayout) NameRect() image.Rectangle {
 	return sl.nameRect
 }
 
+// NamePlateRect returns the name plate rectangle.
+func (sl *SimpleLayout) NamePlateRect() image.Rectangle {
+	return sl.namePlateRect
+}
+
 // FrameRect returns the frame rectangle.
 func (sl *SimpleLayout) FrameRect() image.Rectangle {
 	return sl.frameRect
 }
 
+// AvatarPanelRect returns the avatar panel rectangle.
+func (sl *SimpleLayout) AvatarPanelRect() image.Rectangle {
+	return sl.panelRect
+}
+
 // TextRect returns the text rectangle.
 func (sl *SimpleLayout) TextRect() image.Rectangle {
 	return sl.textRect
@@ -377,38 +500,74 @@ func (sl *SimpleLayout) ChevronRect() image.Rectangle {
 	return sl.chevronRect
 }
 
+// ChoiceRect returns the choice menu rectangle.
+func (sl *SimpleLayout) ChoiceRect() image.Rectangle {
+	return sl.choiceRect
+}
+
+// TailRect returns the speech bubble tail rectangle.
+func (sl *SimpleLayout) TailRect() image.Rectangle {
+	return sl.tailRect
+}
+
 // NewSimpleLayout constructs SimpleLayout simply as possible (for the user.)
 func NewSimpleLayout(tb *TextBox, destRect image.Rectangle) (*SimpleLayout, error) {
 	l := &SimpleLayout{}
+	if tb.speechBubble != nil {
+		destRect = tb.speechBubble.rect(tb.theme, destRect)
+	}
+	spacing := tb.Spacing()
+	if tb.avatarLocation.outside() {
+		l.panelRect, l.avatarRect, destRect = tb.avatarPanelRects(destRect, spacing)
+	}
 	l.frameRect = destRect
 	if tb.nameBox != nil {
-		m := tb.nameBox.MetricsRect()
-		a := tb.nameBox.AdvanceRect()
-		height := (m.Ascent + m.Descent).Ceil()
-		width := a.Ceil()
+		size := tb.nameTagSize()
+		height, width := size.Y, size.X
 		switch tb.namePosition {
 		case NameTopLeftAboveFrame:
 			l.nameRect = image.Rect(destRect.Min.X, destRect.Min.Y, destRect.Min.X+width, destRect.Min.Y+height)
-			l.frameRect.Min.Y += height
+			l.frameRect.Min.Y += height + spacing.NameGap
 		case NameTopCenterAboveFrame:
 			centerX := destRect.Min.X + (destRect.Dx()-width)/2
 			l.nameRect = image.Rect(centerX, destRect.Min.Y, centerX+width, destRect.Min.Y+height)
-			l.frameRect.Min.Y += height
+			l.frameRect.Min.Y += height + spacing.NameGap
+		case NameTopLeftOnFrame, NameTopCenterOnFrame, NameTopRightOnFrame:
+			// Centered on the frame's top border, the frame moves down if the name would stick out above destRect
+			border := tb.frameTopBorder()
+			l.frameRect.Min.Y += max(height/2-border/2, 0)
+			top := l.frameRect.Min.Y + border/2 - height/2
+			l.nameRect = image.Rect(0, top, width, top+height)
 		}
 	}
+	if tb.speechBubble != nil {
+		_, l.tailRect = tb.speechBubble.tail(tb.theme, l.frameRect)
+	}
 	if centerRect, err := tb.calculateCenterRect(l.frameRect); err != nil {
 		return nil, err
 	} else {
-		l.centerRect = centerRect
-		l.textRect = centerRect
+		l.centerRect = centerRect.Inset(spacing.Padding)
+		l.textRect = l.centerRect
 	}
 	if tb.nameBox != nil {
-		m := tb.nameBox.MetricsRect()
-		a := tb.nameBox.AdvanceRect()
-		height := (m.Ascent + m.Descent).Ceil()
-		width := a.Ceil()
+		size := tb.nameTagSize()
+		height, width := size.Y, size.X
 		switch tb.namePosition {
 		case NoName, NameTopLeftAboveFrame, NameTopCenterAboveFrame:
+		case NameTopLeftOnFrame, NameTopCenterOnFrame, NameTopRightOnFrame:
+			x := l.centerRect.Min.X
+			switch tb.namePosition {
+			case NameTopCenterOnFrame:
+				x = l.frameRect.Min.X + (l.frameRect.Dx()-width)/2
+			case NameTopRightOnFrame:
+				x = l.centerRect.Max.X - width
+			}
+			l.nameRect = l.nameRect.Add(image.Pt(x, 0))
+			// A name taller than the border reaches into the frame's center so the text moves down to make room
+			if below := l.nameRect.Max.Y + spacing.NameGap - l.centerRect.Min.Y; below > 0 {
+				l.centerRect.Min.Y += below
+				l.textRect.Min.Y += below
+			}
 		case NameTopCenterInFrame:
 			l.nameRect = image.Rect(0, 0, width, height)
 			l.nameRect.Min.X = l.centerRect.Min.X + (l.centerRect.Dx()-l.nameRect.Dx())/2
@@ -420,43 +579,29 @@ func NewSimpleLayout(tb *TextBox, destRect image.Rectangle) (*SimpleLayout, erro
 			}
 			l.nameRect.Min.Y = l.centerRect.Min.Y
 			l.nameRect.Max.Y = l.centerRect.Min.Y + height
-			l.centerRect.Min.Y += height
-			l.textRect.Min = l.textRect.Min.Add(image.Pt(0, height))
+			l.centerRect.Min.Y += height + spacing.NameGap
+			l.textRect.Min = l.textRect.Min.Add(image.Pt(0, height+spacing.NameGap))
 		}
 	}
-	l.avatarRect = tb.Avatar().Bounds()
-	switch tb.avatarFit {
-	case CenterAvatar:
-	case NearestNeighbour, ApproxBiLinear:
-		dx := float64(l.avatarRect.Dx()) / float64(l.centerRect.Dx())
-		dy := float64(l.avatarRect.Dy()) / float64(l.centerRect.Dy())
-		if dy > 1 && dy > dx {
-			l.avatarRect.Max = image.Point{
-				X: l.avatarRect.Min.X + int(float64(l.avatarRect.Dx())/dy),
-				Y: l.avatarRect.Min.Y + int(float64(l.avatarRect.Dy())/dy),
-			}
-		} else if dx > 1 && dx > dy {
-			l.avatarRect.Max = image.Point{
-				X: l.avatarRect.Min.X + int(float64(l.avatarRect.Dx())/dx),
-				Y: l.avatarRect.Min.Y + int(float64(l.avatarRect.Dy())/dx),
-			}
-		}
+	if !tb.avatarLocation.outside() {
+		ab := tb.Avatar().Bounds()
+		l.avatarRect = image.Rectangle{Min: ab.Min, Max: ab.Min.Add(tb.avatarSlot(l.centerRect.Size()))}
 	}
 	switch tb.avatarLocation {
-	case NoAvatar:
+	case NoAvatar, LeftAvatarPanel, RightAvatarPanel, TopLeftAvatarPanel, TopRightAvatarPanel:
 	case LeftAvatar:
-		l.textRect.Min.X += l.avatarRect.Dx()
+		l.textRect.Min.X += l.avatarRect.Dx() + spacing.AvatarGutter
 		l.avatarRect = image.Rectangle{
 			Min: l.centerRect.Min,
 			Max: image.Point{
-				X: l.textRect.Min.X,
+				X: l.textRect.Min.X - spacing.AvatarGutter,
 				Y: l.centerRect.Max.Y,
 			},
 		}
 	case RightAvatar:
-		l.textRect.Max.X -= l.avatarRect.Dx()
+		l.textRect.Max.X -= l.avatarRect.Dx() + spacing.AvatarGutter
 		l.avatarRect = image.Rectangle{
-			Min: image.Pt(l.textRect.Max.X, l.textRect.Min.Y),
+			Min: image.Pt(l.textRect.Max.X+spacing.AvatarGutter, l.textRect.Min.Y),
 			Max: l.centerRect.Max,
 		}
 	default:
@@ -465,7 +610,7 @@ func NewSimpleLayout(tb *TextBox, destRect image.Rectangle) (*SimpleLayout, erro
 	if tb.nameBox != nil {
 		switch tb.namePosition {
 		case NameLeftAboveAvatarInFrame:
-			if tb.avatarLocation != NoAvatar {
+			if tb.avatarLocation == LeftAvatar || tb.avatarLocation == RightAvatar {
 				l.nameRect = l.nameRect.Add(image.Pt(l.avatarRect.Min.X, 0))
 				break
 			}
@@ -474,13 +619,23 @@ func NewSimpleLayout(tb *TextBox, destRect image.Rectangle) (*SimpleLayout, erro
 			l.nameRect = l.nameRect.Add(image.Pt(l.textRect.Min.X, 0))
 		}
 	}
-	l.chevronRect = tb.theme.Chevron().Bounds()
+	if tb.choiceMenu != nil {
+		height := tb.choiceMenu.height(tb)
+		if height > l.textRect.Dy() {
+			return nil, fmt.Errorf("choice menu is %d pixels tall but the text area is only %d", height, l.textRect.Dy())
+		}
+		l.choiceRect = image.Rect(l.textRect.Min.X, l.textRect.Max.Y-height, l.textRect.Max.X, l.textRect.Max.Y)
+		l.textRect.Max.Y -= height
+	}
+	if tb.moreChevronLocation != NoMoreChevron {
+		l.chevronRect = tb.theme.Chevron().Bounds()
+	}
 	switch tb.moreChevronLocation {
 	case NoMoreChevron, TextEndChevron:
 	case CenterBottomInsideTextFrame, CenterBottomInsideFrame, RightBottomInsideTextFrame, RightBottomInsideFrame:
 		l.textRect.Max.Y -= l.chevronRect.Dy()
 	case CenterBottomOnFrameTextFrame, CenterBottomOnFrameFrame, RightBottomOnFrameTextFrame, RightBottomOnFrameFrame:
-		ydiff := util.Max(l.chevronRect.Dy()-(destRect.Max.Y-l.textRect.Max.Y), 0)
+		ydiff := util.Max(l.chevronRect.Dy()-(l.frameRect.Max.Y-l.textRect.Max.Y), 0)
 		l.textRect.Max.Y -= ydiff
 		l.chevronRect = l.chevronRect.Sub(image.Pt(0, ydiff))
 	default:
@@ -499,9 +654,71 @@ func NewSimpleLayout(tb *TextBox, destRect image.Rectangle) (*SimpleLayout, erro
 	default:
 		return nil, fmt.Errorf("unknown more chevron location %v", tb.moreChevronLocation)
 	}
+	l.chevronRect = l.chevronRect.Add(spacing.ChevronOffset)
+	l.namePlateRect = l.nameRect
+	if np, ok := tb.namePlate(); ok && tb.nameBox != nil {
+		pb, pc := np.NamePlate().Bounds(), np.NamePlateCenter()
+		l.nameRect.Min = l.nameRect.Min.Add(pc.Min.Sub(pb.Min))
+		l.nameRect.Max = l.nameRect.Max.Sub(pb.Max.Sub(pc.Max))
+	}
 	return l, nil
 }
 
+// checkTheme returns an error if the theme is missing an image the text box uses, or panics getting one. For a
+// theme.Validator the error is from Validate explaining why, other problems are left to theme.Validate.
+func (tb *TextBox) checkTheme() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	var missing []string
+	if tb.moreChevronLocation != NoMoreChevron || tb.choiceMenu != nil && tb.choiceMenu.Cursor == nil {
+		if tb.theme.Chevron() == nil {
+			missing = append(missing, "chevron")
+		}
+	}
+	if f, ok := tb.theme.(theme.Frame); ok && f.Frame() == nil {
+		missing = append(missing, "frame")
+	}
+	if tb.avatarLocation != NoAvatar && tb.Avatar() == nil {
+		missing = append(missing, "avatar")
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if v, ok := tb.theme.(theme.Validator); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%s: %w", strings.Join(missing, ", "), theme.ErrNoImage)
+}
+
+// namePlate returns the theme's name plate, ok is false if it doesn't have one
+func (tb *TextBox) namePlate() (theme.NamePlate, bool) {
+	np, ok := tb.theme.(theme.NamePlate)
+	return np, ok && np.NamePlate() != nil
+}
+
+// nameTagSize is the size of the name tag including the borders of the theme's name plate
+func (tb *TextBox) nameTagSize() image.Point {
+	m := tb.nameBox.MetricsRect()
+	size := image.Pt(tb.nameBox.AdvanceRect().Ceil(), (m.Ascent + m.Descent).Ceil())
+	if np, ok := tb.namePlate(); ok {
+		size = size.Add(np.NamePlate().Bounds().Size().Sub(np.NamePlateCenter().Size()))
+	}
+	return size
+}
+
+// frameTopBorder is the height of the frame's top border
+func (tb *TextBox) frameTopBorder() int {
+	if f, ok := tb.theme.(theme.Frame); ok {
+		return f.FrameCenter().Min.Y - f.Frame().Bounds().Min.Y
+	}
+	return 0
+}
+
 // calculateCenterRect calculates the size of the center rectangle based on the provided theme
 func (tb *TextBox) calculateCenterRect(destRect image.Rectangle) (image.Rectangle, error) {
 	textRect := destRect
@@ -528,8 +745,12 @@ func drawFrame(t theme.Theme, target wordwrap.Image, options ...wordwrap.DrawOpt
 				f = option(f)
 			}
 		}
+		mode := frame.Stretched
+		if ft, ok := t.(theme.FrameTiling); ok && ft.FrameTiled() {
+			mode = frame.Repeating
+		}
 		ttb := target.Bounds()
-		fd := frame.NewFrame(ttb, f, fc, frame.Stretched)
+		fd := frame.NewFrame(ttb, f, fc, mode)
 		draw.Draw(target, ttb, fd, fd.Bounds().Min, draw.Over)
 	default:
 		return fmt.Errorf("invalid theme, missing a frame drawer")
@@ -542,6 +763,8 @@ func drawFrame(t theme.Theme, target wordwrap.Image, options ...wordwrap.DrawOpt
 type Page struct {
 	ls       []wordwrap.Line
 	boxCount int
+	// rect is the text rectangle the page was wrapped into
+	rect image.Rectangle
 }
 
 // CalculateAllPages calculates the box positioning of all remain pages in advance
@@ -550,7 +773,7 @@ func (tb *TextBox) CalculateAllPages(destSize image.Point) (int, error) {
 		Min: image.Point{},
 		Max: destSize,
 	}
-	l, err := NewSimpleLayout(tb, destRect)
+	l, err := tb.newLayout(destRect)
 	if err != nil {
 		return len(tb.pages), err
 	}
@@ -573,12 +796,38 @@ func (tb *TextBox) CalculateAllPages(destSize image.Point) (int, error) {
 // err is err
 // To determine if you're at the end the only way of doing it as of writing is to wait for; lastPage = true,
 // userInputAccepted = false, wait = -1
+// If the theme implements theme.AnimatedChevron then while waiting for user input each call draws the next chevron
+// frame and returns its duration as wait, call Continue when the player responds to move on.
 func (tb *TextBox) DrawNextFrame(target wordwrap.Image) (lastPage bool, userInputAccepted bool, wait time.Duration, err error) {
+	if tb.chevronWait != nil {
+		return tb.drawChevronFrame(target)
+	}
 	if tb.animation == nil {
 		next, err := tb.DrawNextPageFrame(target)
-		return next, true, 0, err
+		if err == nil && !next {
+			return true, false, -1, nil
+		}
+		lastPage, userInputAccepted, wait = next, true, 0
+		if err != nil {
+			return lastPage, userInputAccepted, wait, err
+		}
+	} else if lastPage, userInputAccepted, wait, err = tb.animation.DrawOption(target); err != nil {
+		return
+	}
+	if userInputAccepted && wait <= 0 {
+		if d, ok := tb.startChevronAnimation(lastPage); ok {
+			wait = d
+		}
+	}
+	return
+}
+
+// Skip completes the animation of the current page so the next DrawNextFrame draws the whole page and accepts user
+// input. Does nothing if there is no animation or the page is already complete.
+func (tb *TextBox) Skip() {
+	if tb.animation != nil {
+		tb.animation.Skip()
 	}
-	return tb.animation.DrawOption(target)
 }
 
 // DrawNextPageFrame Draws the next frame ignores animation. Please use either function but be very careful if you use
@@ -595,31 +844,86 @@ func (tb *TextBox) DrawNextPageFrame(target wordwrap.Image, opts ...wordwrap.Dra
 }
 
 // drawPage draws the entire page.
-func (tb *TextBox) drawPage(target wordwrap.Image, layout *SimpleLayout, page *Page, opts ...wordwrap.DrawOption) (bool, error) {
+func (tb *TextBox) drawPage(target wordwrap.Image, layout Layout, page *Page, opts ...wordwrap.DrawOption) (bool, error) {
+	return tb.drawLines(target, layout, page.ls, 0, tb.HasNext(), tb.HasNext(), opts...)
+}
+
+// drawLines draws the frame and everything around the text, then the lines aligned to the text rect, moved up by scroll
+// pixels and clipped to it. chevron is whether the more chevron is drawn, more is whether there is text after the
+// lines, the choice menu is drawn when there isn't.
+func (tb *TextBox) drawLines(target wordwrap.Image, layout Layout, ls []wordwrap.Line, scroll int, chevron, more bool, opts ...wordwrap.DrawOption) (bool, error) {
+	tb.shown = &shownLines{
+		layout:  layout,
+		ls:      ls,
+		scroll:  scroll,
+		chevron: chevron,
+		more:    more,
+	}
 	if err := drawFrame(tb.theme, target.SubImage(layout.FrameRect()).(wordwrap.Image), opts...); err != nil {
 		return false, err
 	}
+	tb.drawTail(target, layout, opts...)
+	tb.drawAvatarPanel(target, layout, opts...)
 	subImage := target.SubImage(layout.TextRect()).(wordwrap.Image)
-	tb.drawAvatar(target, layout, opts...)
-	if tb.HasNext() {
+	if chevron {
 		tb.drawMoreChevron(target, layout, opts...)
 	}
 	if tb.name != "" {
 		tb.drawNameTag(target, layout, opts...)
 	}
+	if tb.choiceMenu != nil && !more {
+		if err := tb.drawChoices(target, layout, opts...); err != nil {
+			return false, err
+		}
+	}
+	tr := layout.TextRect()
+	height := 0
+	for _, l := range ls {
+		height += l.Size().Dy()
+	}
+	top := tb.verticalOffset(height, tr.Dy()) - scroll
+	// Lines are drawn relative to the top of the image they are given, so a line cut off by the top of the text rect
+	// has to be drawn elsewhere first
+	offscreen := top < 0 || scroll != 0
+	opts = tb.chevronOptions(opts)
+	// The avatar is drawn after the text as the expression depends on how much of the text is shown
+	var ft *faceTracker
+	if tb.avatarProvider != nil && len(ls) > 0 {
+		ft = &faceTracker{face: tb.faceBefore(ls[0])}
+		opts = append(opts, wordwrap.BoxRecorder(ft.record))
+	}
 	if tb.spaceMap != nil {
 		opts = append(opts, wordwrap.BoxRecorder(func(box wordwrap.Box, min, max image.Point, bps *wordwrap.BoxPositionStats) {
+			r := image.Rectangle{Min: min, Max: max}
+			if offscreen {
+				if r = r.Intersect(tr); r.Empty() {
+					return
+				}
+			}
 			tb.spaceMap.Add(&BoxShape{
 				Box:  box,
-				Rect: image.Rectangle{Min: min, Max: max},
+				Rect: r,
 			}, 0)
 		}))
 	}
-	if err := tb.wrapper.RenderLines(subImage, page.ls, layout.TextRect().Min, opts...); err != nil {
-		return false, err
+	if !offscreen {
+		if err := tb.renderLines(subImage, ls, tr, more, opts...); err != nil {
+			return false, err
+		}
+	} else {
+		scrolled := image.NewRGBA(image.Rect(tr.Min.X, tr.Min.Y+top, tr.Max.X, tr.Min.Y+top+height))
+		if err := tb.renderLines(scrolled, ls, scrolled.Rect, more, opts...); err != nil {
+			return false, err
+		}
+		draw.Draw(subImage, tr, scrolled, tr.Min, draw.Over)
+	}
+	if ft != nil {
+		tb.drawAvatar(target, layout, tb.avatarImage(ft.face, ft.talking(ls)), opts...)
+	} else {
+		tb.drawAvatar(target, layout, tb.avatarImage(tb.face, false), opts...)
 	}
 	for _, postDrawer := range tb.postDraw {
-		if err := postDrawer.PostDraw(target, layout, page.ls, opts...); err != nil {
+		if err := postDrawer.PostDraw(target, layout, ls, opts...); err != nil {
 			return false, err
 		}
 	}
@@ -644,7 +948,15 @@ func (b *BoxShape) String() string {
 }
 
 func (b *BoxShape) ID() interface{} {
-	if i, ok := b.Box.(wordwrap.Identifier); ok {
+	return boxID(b.Box)
+}
+
+// boxID returns the ID given to a box's content, or nil
+func boxID(b wordwrap.Box) interface{} {
+	if pb, ok := b.(*partialBox); ok {
+		b = pb.Box
+	}
+	if i, ok := b.(wordwrap.Identifier); ok {
 		return i.ID()
 	}
 	return nil
@@ -652,8 +964,8 @@ func (b *BoxShape) ID() interface{} {
 
 // getNextPage calculates the next page and updates various info. If all results are nil then it means there is nothing
 // left
-func (tb *TextBox) getNextPage(bounds image.Rectangle) (*SimpleLayout, *Page, error) {
-	layout, err := NewSimpleLayout(tb, bounds)
+func (tb *TextBox) getNextPage(bounds image.Rectangle) (Layout, *Page, error) {
+	layout, err := tb.newLayout(bounds)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -674,9 +986,26 @@ func (tb *TextBox) getNextPage(bounds image.Rectangle) (*SimpleLayout, *Page, er
 	return layout, page, nil
 }
 
+// animationPage returns the layout for a page an animation is part way through (such as one restored from a State), or
+// the next page if it isn't part way through one.
+func (tb *TextBox) animationPage(bounds image.Rectangle, page *Page) (Layout, *Page, error) {
+	if page == nil {
+		return tb.getNextPage(bounds)
+	}
+	layout, err := tb.newLayout(bounds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return layout, page, nil
+}
+
 // drawMoreChevron draws the "next page" indicator.
 func (tb *TextBox) drawMoreChevron(target wordwrap.Image, layout Layout, options ...wordwrap.DrawOption) {
-	cti := tb.theme.Chevron()
+	switch tb.moreChevronLocation {
+	case NoMoreChevron, TextEndChevron:
+		return
+	}
+	cti := tb.chevronImage()
 	for _, option := range options {
 		switch option := option.(type) {
 		case wordwrap.SourceImageMapper:
@@ -684,11 +1013,7 @@ func (tb *TextBox) drawMoreChevron(target wordwrap.Image, layout Layout, options
 		}
 	}
 	ctr := cti.Bounds()
-	switch tb.moreChevronLocation {
-	case NoMoreChevron, TextEndChevron:
-	default:
-		draw.Draw(target.SubImage(layout.ChevronRect()).(wordwrap.Image), layout.ChevronRect(), cti, ctr.Min, draw.Over)
-	}
+	draw.Draw(target.SubImage(layout.ChevronRect()).(wordwrap.Image), layout.ChevronRect(), cti, ctr.Min, draw.Over)
 }
 
 // drawNameTag draws the name tag.
@@ -697,6 +1022,7 @@ func (tb *TextBox) drawNameTag(target wordwrap.Image, layout Layout, options ...
 		switch tb.namePosition {
 		case NoName:
 		default:
+			tb.drawNamePlate(target, layout, options...)
 			m := tb.nameBox.MetricsRect()
 			config := wordwrap.NewDrawConfig(options...)
 			var bb = tb.nameBox
@@ -712,11 +1038,32 @@ func (tb *TextBox) drawNameTag(target wordwrap.Image, layout Layout, options ...
 	}
 }
 
+// drawNamePlate draws the theme's name plate behind the name tag
+func (tb *TextBox) drawNamePlate(target wordwrap.Image, layout Layout, options ...wordwrap.DrawOption) {
+	np, ok := tb.namePlate()
+	if !ok {
+		return
+	}
+	npl, ok := layout.(NamePlateLayout)
+	if !ok || npl.NamePlateRect().Empty() {
+		return
+	}
+	pr := npl.NamePlateRect()
+	pi := np.NamePlate()
+	for _, option := range options {
+		switch option := option.(type) {
+		case wordwrap.SourceImageMapper:
+			pi = option(pi)
+		}
+	}
+	fd := frame.NewFrame(pr, pi, np.NamePlateCenter(), frame.Stretched)
+	draw.Draw(target.SubImage(pr).(wordwrap.Image), pr, fd, fd.Bounds().Min, draw.Over)
+}
+
 // drawAvatar draws the avatar image.
-func (tb *TextBox) drawAvatar(target wordwrap.Image, layout Layout, options ...wordwrap.DrawOption) {
+func (tb *TextBox) drawAvatar(target wordwrap.Image, layout Layout, avatarImg image.Image, options ...wordwrap.DrawOption) {
 	switch tb.avatarLocation {
-	case RightAvatar, LeftAvatar:
-		avatarImg := tb.Avatar()
+	case RightAvatar, LeftAvatar, LeftAvatarPanel, RightAvatarPanel, TopLeftAvatarPanel, TopRightAvatarPanel:
 		for _, option := range options {
 			switch option := option.(type) {
 			case wordwrap.SourceImageMapper:
@@ -726,10 +1073,8 @@ func (tb *TextBox) drawAvatar(target wordwrap.Image, layout Layout, options ...w
 		air := avatarImg.Bounds()
 		atr := layout.AvatarRect()
 		switch tb.avatarFit {
-		case NearestNeighbour:
-			draw.NearestNeighbor.Scale(target.SubImage(layout.AvatarRect()).(wordwrap.Image), layout.AvatarRect(), avatarImg, air, draw.Over, nil)
-		case ApproxBiLinear:
-			draw.ApproxBiLinear.Scale(target.SubImage(layout.AvatarRect()).(wordwrap.Image), layout.AvatarRect(), avatarImg, air, draw.Over, nil)
+		case NearestNeighbour, ApproxBiLinear, CatmullRom:
+			tb.avatarScaler().Scale(target.SubImage(layout.AvatarRect()).(wordwrap.Image), layout.AvatarRect(), avatarImg, air, draw.Over, nil)
 		case NoAvatarFit:
 			draw.Draw(target.SubImage(layout.AvatarRect()).(wordwrap.Image), layout.AvatarRect(), avatarImg, air.Min, draw.Over)
 		case CenterAvatar:
@@ -741,20 +1086,45 @@ func (tb *TextBox) drawAvatar(target wordwrap.Image, layout Layout, options ...w
 			if dx < 0 {
 				dx = 0
 			}
+			switch tb.avatarAnchor {
+			case AvatarAnchorTop:
+				dy = 0
+			case AvatarAnchorBottom:
+				dy *= 2
+			}
 			air = air.Add(image.Pt(dx/2, dy/2))
 			draw.Draw(target.SubImage(layout.AvatarRect()).(wordwrap.Image), layout.AvatarRect(), avatarImg, air.Min, draw.Over)
+		case Contain, Cover, IntegerScale:
+			dr := tb.avatarAnchor.place(tb.avatarFitSize(air.Size(), atr.Size()), atr)
+			tb.avatarScaler().Scale(target.SubImage(layout.AvatarRect()).(wordwrap.Image), dr, avatarImg, air, draw.Over, nil)
 		}
 	}
 }
 
 // Avatar returns the correct avatar (if you have overwritten the theme etc.)
 func (tb *TextBox) Avatar() image.Image {
+	if tb.avatarProvider != nil {
+		if i := tb.avatarProvider.AvatarFrame(string(tb.face), false, 0); i != nil {
+			return i
+		}
+	}
 	if tb.avatar != nil {
 		return tb.avatar
 	}
 	return tb.theme.Avatar()
 }
 
+// Spacing returns the spacing set by the Spacing option, otherwise the theme's default if it has one
+func (tb *TextBox) Spacing() theme.Spacing {
+	if tb.spacing != nil {
+		return *tb.spacing
+	}
+	if s, ok := tb.theme.(theme.Spaced); ok {
+		return s.Spacing()
+	}
+	return theme.Spacing{}
+}
+
 // HasNext returns if there is a next page, this doesn't take into consideration if there is an animation
 func (tb *TextBox) HasNext() bool {
 	if len(tb.pages) > tb.nextPage {
@@ -762,3 +1132,35 @@ func (tb *TextBox) HasNext() bool {
 	}
 	return tb.wrapper.HasNext()
 }
+
+// PageCount returns the number of pages calculated so far. Use CalculateAllPages first if you need the total.
+func (tb *TextBox) PageCount() int {
+	return len(tb.pages)
+}
+
+// CurrentPage returns the zero based index of the page most recently drawn, or -1 if no page has been drawn yet
+func (tb *TextBox) CurrentPage() int {
+	return tb.nextPage - 1
+}
+
+// SeekPage sets the page that the next draw call will render. Only pages that have already been calculated can be
+// sought to, use CalculateAllPages to be able to jump forward. Any animation in progress is reset.
+func (tb *TextBox) SeekPage(n int) error {
+	if n < 0 || n >= len(tb.pages) {
+		return fmt.Errorf("page %d out of range, %d pages calculated", n, len(tb.pages))
+	}
+	tb.nextPage = n
+	tb.chevronWait = nil
+	if tb.animation != nil {
+		tb.animation.reset()
+	}
+	return nil
+}
+
+// PreviousPage sets the next draw call to render the page before the one most recently drawn.
+func (tb *TextBox) PreviousPage() error {
+	if tb.CurrentPage() <= 0 {
+		return errors.New("no previous page")
+	}
+	return tb.SeekPage(tb.CurrentPage() - 1)
+}
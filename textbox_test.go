@@ -1,13 +1,22 @@
 package rpgtextbox
 
 import (
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
+	"image/draw"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/theme/cache"
+	"github.com/arran4/golang-rpg-textbox/theme/dynamic"
+	"github.com/arran4/golang-rpg-textbox/theme/fromdirpng"
 	"github.com/arran4/golang-rpg-textbox/theme/simple"
 	"github.com/arran4/golang-rpg-textbox/util"
+	wordwrap "github.com/arran4/golang-wordwrap"
 )
 
 func TestNamePositioning(t *testing.T) {
@@ -63,3 +72,457 @@ func TestNamePositioning(t *testing.T) {
 		})
 	}
 }
+
+func TestPageNavigation(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	textBoxSize := image.Pt(300, 100)
+	text := "This is a long piece of text which is going to need several pages to display in such a small text box. " +
+		"It keeps going so that there is plenty to page back and forth between while testing."
+	tb, err := NewSimpleTextBox(theme, text, textBoxSize, NewBoxByBoxAnimation())
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	pages, err := tb.CalculateAllPages(textBoxSize)
+	if err != nil {
+		t.Fatalf("Calculate pages error: %v", err)
+	}
+	if pages < 3 {
+		t.Fatalf("Expected at least 3 pages got %d", pages)
+	}
+	if tb.PageCount() != pages {
+		t.Errorf("PageCount() = %d, want %d", tb.PageCount(), pages)
+	}
+	if tb.CurrentPage() != -1 {
+		t.Errorf("CurrentPage() = %d before drawing, want -1", tb.CurrentPage())
+	}
+	if err := tb.PreviousPage(); err == nil {
+		t.Errorf("Expected error going back before the first page")
+	}
+	i := image.NewRGBA(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y))
+	for p := 0; p < 2; p++ {
+		if _, err := tb.DrawNextPageFrame(i); err != nil {
+			t.Fatalf("Draw next frame error: %v", err)
+		}
+	}
+	if tb.CurrentPage() != 1 {
+		t.Errorf("CurrentPage() = %d, want 1", tb.CurrentPage())
+	}
+	if _, _, _, err := tb.DrawNextFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if err := tb.PreviousPage(); err != nil {
+		t.Fatalf("PreviousPage error: %v", err)
+	}
+	if _, ui, _, err := tb.DrawNextFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	} else if ui {
+		t.Errorf("Expected the animation to restart after changing page")
+	}
+	if tb.CurrentPage() != 1 {
+		t.Errorf("CurrentPage() = %d after PreviousPage, want 1", tb.CurrentPage())
+	}
+	if err := tb.SeekPage(pages); err == nil {
+		t.Errorf("Expected error seeking past the last page")
+	}
+	if err := tb.SeekPage(pages - 1); err != nil {
+		t.Fatalf("SeekPage error: %v", err)
+	}
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if tb.HasNext() {
+		t.Errorf("Expected no next page after drawing the last page")
+	}
+}
+
+// portraitLayout puts the avatar above the text
+type portraitLayout struct {
+	*SimpleLayout
+	avatarRect, textRect image.Rectangle
+}
+
+func (pl *portraitLayout) AvatarRect() image.Rectangle {
+	return pl.avatarRect
+}
+
+func (pl *portraitLayout) TextRect() image.Rectangle {
+	return pl.textRect
+}
+
+// layoutRecorder is a PostDrawer which records the layouts it was given
+type layoutRecorder []Layout
+
+func (lr *layoutRecorder) PostDraw(target wordwrap.Image, layout Layout, ls []wordwrap.Line, options ...wordwrap.DrawOption) error {
+	*lr = append(*lr, layout)
+	return nil
+}
+
+func (lr *layoutRecorder) apply(box *TextBox) {
+	box.postDraw = append(box.postDraw, lr)
+}
+
+func TestLayoutFactory(t *testing.T) {
+	theme, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	textBoxSize := image.Pt(200, 300)
+	factory := LayoutFactory(func(tb *TextBox, destRect image.Rectangle) (Layout, error) {
+		sl, err := NewSimpleLayout(tb, destRect)
+		if err != nil {
+			return nil, err
+		}
+		center := sl.CenterRect()
+		avatarHeight := tb.Avatar().Bounds().Dy()
+		return &portraitLayout{
+			SimpleLayout: sl,
+			avatarRect:   image.Rect(center.Min.X, center.Min.Y, center.Max.X, center.Min.Y+avatarHeight),
+			textRect:     image.Rect(center.Min.X, center.Min.Y+avatarHeight, center.Max.X, center.Max.Y),
+		}, nil
+	})
+	lr := &layoutRecorder{}
+	tb, err := NewSimpleTextBox(theme, "Portrait on top", textBoxSize, factory, LeftAvatar, lr)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(image.Rect(0, 0, textBoxSize.X, textBoxSize.Y))
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if len(*lr) != 1 {
+		t.Fatalf("Expected the PostDrawer to be called once got %d", len(*lr))
+	}
+	pl, ok := (*lr)[0].(*portraitLayout)
+	if !ok {
+		t.Fatalf("Expected the PostDrawer to be given the factory's layout got %T", (*lr)[0])
+	}
+	if tb.pages[0].rect != pl.TextRect() {
+		t.Errorf("Page wrapped into %v want %v", tb.pages[0].rect, pl.TextRect())
+	}
+	failing := LayoutFactory(func(tb *TextBox, destRect image.Rectangle) (Layout, error) {
+		return nil, errors.New("no room")
+	})
+	if _, err := NewSimpleTextBox(theme, "Portrait on top", textBoxSize, failing); err == nil {
+		t.Errorf("Expected the layout factory's error")
+	}
+}
+
+// spacedTheme adds default spacing to a theme
+type spacedTheme struct {
+	theme.Theme
+	frame   theme.Frame
+	spacing theme.Spacing
+}
+
+func (st *spacedTheme) Frame() image.Image {
+	return st.frame.Frame()
+}
+
+func (st *spacedTheme) FrameCenter() image.Rectangle {
+	return st.frame.FrameCenter()
+}
+
+func (st *spacedTheme) Spacing() theme.Spacing {
+	return st.spacing
+}
+
+func TestSpacing(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	bounds := image.Rect(0, 0, 600, 250)
+	spacing := Spacing{Padding: 4, AvatarGutter: 6, NameGap: 3, ChevronOffset: image.Pt(-2, -1)}
+	themeSpacing := theme.Spacing{Padding: 10}
+	tests := []struct {
+		name     string
+		theme    theme.Theme
+		options  []Option
+		expected theme.Spacing
+	}{
+		{"Option", th, []Option{spacing}, theme.Spacing(spacing)},
+		{"ThemeDefault", &spacedTheme{th, th, themeSpacing}, nil, themeSpacing},
+		{"OptionOverridesTheme", &spacedTheme{th, th, themeSpacing}, []Option{spacing}, theme.Spacing(spacing)},
+	}
+	for _, position := range []NamePositions{NameTopLeftAboveTextInFrame, NameTopLeftAboveFrame} {
+		for _, tt := range tests {
+			name := fmt.Sprintf("%s/%d", tt.name, position)
+			options := append([]Option{Name("Spaced"), position, LeftAvatar, RightBottomInsideTextFrame}, tt.options...)
+			tb, err := NewSimpleTextBox(tt.theme, "Some text", bounds.Size(), options...)
+			if err != nil {
+				t.Fatalf("%s: Error creating text box: %v", name, err)
+			}
+			if tb.Spacing() != tt.expected {
+				t.Errorf("%s: spacing %v want %v", name, tb.Spacing(), tt.expected)
+			}
+			unspaced, err := NewSimpleTextBox(th, "Some text", bounds.Size(), Name("Spaced"), position, LeftAvatar, RightBottomInsideTextFrame)
+			if err != nil {
+				t.Fatalf("%s: Error creating text box: %v", name, err)
+			}
+			l, err := NewSimpleLayout(tb, bounds)
+			if err != nil {
+				t.Fatalf("%s: Error creating layout: %v", name, err)
+			}
+			ul, err := NewSimpleLayout(unspaced, bounds)
+			if err != nil {
+				t.Fatalf("%s: Error creating layout: %v", name, err)
+			}
+			s := tt.expected
+			if got, want := l.AvatarRect().Min, ul.AvatarRect().Min.Add(image.Pt(s.Padding, s.Padding+s.NameGap)); got != want {
+				t.Errorf("%s: avatar at %v want %v", name, got, want)
+			}
+			if gutter := l.TextRect().Min.X - l.AvatarRect().Max.X; gutter != s.AvatarGutter {
+				t.Errorf("%s: avatar gutter %d want %d", name, gutter, s.AvatarGutter)
+			}
+			if gap := l.TextRect().Min.Y - l.NameRect().Max.Y; position == NameTopLeftAboveTextInFrame && gap != s.NameGap {
+				t.Errorf("%s: name gap %d want %d", name, gap, s.NameGap)
+			}
+			if gap := l.FrameRect().Min.Y - l.NameRect().Max.Y; position == NameTopLeftAboveFrame && gap != s.NameGap {
+				t.Errorf("%s: name to frame gap %d want %d", name, gap, s.NameGap)
+			}
+			if right := ul.TextRect().Max.X - l.TextRect().Max.X; right != s.Padding {
+				t.Errorf("%s: text ends %d pixels further in want %d", name, right, s.Padding)
+			}
+			if got, want := l.ChevronRect().Min, ul.ChevronRect().Min.Sub(image.Pt(s.Padding, s.Padding)).Add(s.ChevronOffset); got != want {
+				t.Errorf("%s: chevron at %v want %v", name, got, want)
+			}
+		}
+	}
+}
+
+func TestSpacingWrapped(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	themeSpacing := theme.Spacing{Padding: 10, NameGap: 2}
+	for name, wt := range wrappedThemes(t, &spacedTheme{th, th, themeSpacing}) {
+		tb, err := NewSimpleTextBox(wt, "Some text", image.Pt(600, 250))
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		if tb.Spacing() != themeSpacing {
+			t.Errorf("%s: spacing %v want %v", name, tb.Spacing(), themeSpacing)
+		}
+	}
+}
+
+// namePlateTheme adds a name plate to a theme
+type namePlateTheme struct {
+	*spacedTheme
+	plate image.Image
+}
+
+func (nt *namePlateTheme) NamePlate() image.Image {
+	return nt.plate
+}
+
+func (nt *namePlateTheme) NamePlateCenter() image.Rectangle {
+	return image.Rect(4, 4, 8, 8)
+}
+
+func TestNamePlate(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	plate := image.NewUniform(color.RGBA{R: 255, A: 255})
+	pi := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	draw.Draw(pi, pi.Bounds(), plate, image.Point{}, draw.Src)
+	nt := &namePlateTheme{&spacedTheme{Theme: th, frame: th}, pi}
+	bounds := image.Rect(0, 0, 600, 150)
+	border := th.FrameCenter().Min.Y - th.Frame().Bounds().Min.Y
+	for _, position := range []NamePositions{NameTopLeftOnFrame, NameTopCenterOnFrame, NameTopRightOnFrame, NameTopLeftAboveTextInFrame} {
+		name := fmt.Sprintf("Position%d", position)
+		tb, err := NewSimpleTextBox(nt, "Some text", bounds.Size(), Name("Plated"), position)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		l, err := NewSimpleLayout(tb, bounds)
+		if err != nil {
+			t.Fatalf("%s: Error creating layout: %v", name, err)
+		}
+		pr, nr := l.NamePlateRect(), l.NameRect()
+		if nr.Min.Sub(pr.Min) != image.Pt(4, 4) || pr.Max.Sub(nr.Max) != image.Pt(4, 4) {
+			t.Errorf("%s: name %v not inside the plate's center %v", name, nr, pr)
+		}
+		if l.TextRect().Min.Y < pr.Max.Y {
+			t.Errorf("%s: text %v overlaps the name plate %v", name, l.TextRect(), pr)
+		}
+		if position != NameTopLeftAboveTextInFrame {
+			if pr.Min.Y >= l.FrameRect().Min.Y+border || pr.Max.Y <= l.FrameRect().Min.Y {
+				t.Errorf("%s: plate %v does not cross the top border of %v", name, pr, l.FrameRect())
+			}
+			if !pr.In(bounds) {
+				t.Errorf("%s: plate %v outside of %v", name, pr, bounds)
+			}
+		}
+		switch position {
+		case NameTopLeftOnFrame:
+			if pr.Min.X != l.CenterRect().Min.X {
+				t.Errorf("%s: plate %v not on the left of %v", name, pr, l.CenterRect())
+			}
+		case NameTopCenterOnFrame:
+			if d := (pr.Min.X - l.FrameRect().Min.X) - (l.FrameRect().Max.X - pr.Max.X); d < -1 || d > 1 {
+				t.Errorf("%s: plate %v not centered on %v", name, pr, l.FrameRect())
+			}
+		case NameTopRightOnFrame:
+			if pr.Max.X != l.CenterRect().Max.X {
+				t.Errorf("%s: plate %v not on the right of %v", name, pr, l.CenterRect())
+			}
+		}
+		i := image.NewRGBA(bounds)
+		if _, err := tb.DrawNextPageFrame(i); err != nil {
+			t.Fatalf("%s: Draw next frame error: %v", name, err)
+		}
+		if c := i.At(pr.Min.X+1, pr.Min.Y+1); c != plate.C {
+			t.Errorf("%s: expected the plate to be drawn at %v got %v", name, pr.Min, c)
+		}
+	}
+}
+
+func TestNamePlateBaseLayout(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	plate := image.NewUniform(color.RGBA{R: 255, A: 255})
+	pi := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	draw.Draw(pi, pi.Bounds(), plate, image.Point{}, draw.Src)
+	nt := &namePlateTheme{&spacedTheme{Theme: th, frame: th}, pi}
+	bounds := image.Rect(0, 0, 600, 150)
+	var pr image.Rectangle
+	tb, err := NewSimpleTextBox(nt, "Some text", bounds.Size(), Name("Plated"), NameTopLeftOnFrame, LayoutFactory(func(tb *TextBox, destRect image.Rectangle) (Layout, error) {
+		sl, err := NewSimpleLayout(tb, destRect)
+		if err != nil {
+			return nil, err
+		}
+		pr = sl.NamePlateRect()
+		return &baseLayout{sl}, nil
+	}))
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	i := image.NewRGBA(bounds)
+	if _, err := tb.DrawNextPageFrame(i); err != nil {
+		t.Fatalf("Draw next frame error: %v", err)
+	}
+	if c := i.At(pr.Min.X+1, pr.Min.Y+1); c == plate.C {
+		t.Errorf("Expected no plate for a layout without NamePlateRect")
+	}
+}
+
+// wrappedThemes wraps src in each of the wrapping themes
+func wrappedThemes(t *testing.T, src cache.Source) map[string]theme.Theme {
+	t.Helper()
+	ct, err := cache.New(src, nil)
+	if err != nil {
+		t.Fatalf("Failed to create cache theme: %v", err)
+	}
+	return map[string]theme.Theme{
+		"Cache":   ct,
+		"Dynamic": dynamic.New(src, "", "", ""),
+	}
+}
+
+func TestNamePlateWrapped(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	pi := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	nt := &namePlateTheme{&spacedTheme{Theme: th, frame: th}, pi}
+	bounds := image.Rect(0, 0, 600, 150)
+	want, err := NewSimpleTextBox(nt, "Some text", bounds.Size(), Name("Plated"), NameTopLeftOnFrame)
+	if err != nil {
+		t.Fatalf("Error creating text box: %v", err)
+	}
+	wl, err := NewSimpleLayout(want, bounds)
+	if err != nil {
+		t.Fatalf("Error creating layout: %v", err)
+	}
+	for name, wt := range wrappedThemes(t, nt) {
+		tb, err := NewSimpleTextBox(wt, "Some text", bounds.Size(), Name("Plated"), NameTopLeftOnFrame)
+		if err != nil {
+			t.Fatalf("%s: Error creating text box: %v", name, err)
+		}
+		l, err := NewSimpleLayout(tb, bounds)
+		if err != nil {
+			t.Fatalf("%s: Error creating layout: %v", name, err)
+		}
+		if l.NameRect() != wl.NameRect() || l.NamePlateRect() != wl.NamePlateRect() {
+			t.Errorf("%s: name %v plate %v want %v %v", name, l.NameRect(), l.NamePlateRect(), wl.NameRect(), wl.NamePlateRect())
+		}
+	}
+	for name, wt := range wrappedThemes(t, th) {
+		if err := theme.Validate(wt); err != nil {
+			t.Errorf("%s: Expected a wrapped theme without a name plate to be valid got %v", name, err)
+		}
+		if _, ok := (&TextBox{theme: wt}).namePlate(); ok {
+			t.Errorf("%s: Expected no name plate", name)
+		}
+	}
+}
+
+// brokenTheme panics like a theme missing its files
+type brokenTheme struct {
+	theme.Theme
+}
+
+func (bt *brokenTheme) Chevron() image.Image {
+	panic("chevron.png missing")
+}
+
+// frameTheme is a theme with a frame
+type frameTheme interface {
+	theme.Theme
+	theme.Frame
+}
+
+// noChevronTheme has no chevron
+type noChevronTheme struct {
+	frameTheme
+}
+
+func (nt *noChevronTheme) Chevron() image.Image {
+	return nil
+}
+
+func TestInvalidTheme(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	if err := theme.Validate(&brokenTheme{th}); err == nil || !strings.Contains(err.Error(), "chevron.png missing") {
+		t.Errorf("Expected the theme's panic as an error got %v", err)
+	}
+	if _, err := NewSimpleTextBox(&brokenTheme{th}, "Some text", image.Pt(200, 100), CenterBottomInsideFrame); err == nil || !strings.Contains(err.Error(), "chevron.png missing") {
+		t.Errorf("Expected the theme's panic as an error got %v", err)
+	}
+	missing, err := fromdirpng.New(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("Failed to create directory theme: %v", err)
+	}
+	if _, err := NewSimpleTextBox(missing, "Some text", image.Pt(200, 100), LeftAvatar, CenterBottomInsideFrame); err == nil || !strings.Contains(err.Error(), "avatar.png") {
+		t.Errorf("Expected the missing files as an error got %v", err)
+	}
+	if _, err := NewSimpleTextBox(dynamic.New(th, "", "{{", ""), "Some text", image.Pt(200, 100)); err == nil || !strings.Contains(err.Error(), "pattern") {
+		t.Errorf("Expected the invalid pattern as an error got %v", err)
+	}
+	if _, err := NewSimpleTextBox(nil, "Some text", image.Pt(200, 100)); !errors.Is(err, theme.ErrNoTheme) {
+		t.Errorf("Expected ErrNoTheme without a theme got %v", err)
+	}
+	if _, err := NewSimpleTextBox(&noChevronTheme{th}, "Some text", image.Pt(200, 100), CenterBottomInsideFrame); !errors.Is(err, theme.ErrNoImage) {
+		t.Errorf("Expected ErrNoImage without a chevron got %v", err)
+	}
+	tb, err := NewSimpleTextBox(&noChevronTheme{th}, strings.Repeat("Some text ", 50), image.Pt(200, 100), NoMoreChevron)
+	if err != nil {
+		t.Fatalf("Expected no chevron to be fine with NoMoreChevron got %v", err)
+	}
+	if _, err := tb.DrawNextPageFrame(image.NewRGBA(image.Rect(0, 0, 200, 100))); err != nil {
+		t.Errorf("Drawing without a chevron: %v", err)
+	}
+}
@@ -13,9 +13,13 @@ type Source interface {
 
 type t struct {
 	Source
-	chevron image.Image
-	frame   image.Image
-	avatar  image.Image
+	chevron     image.Image
+	frame       image.Image
+	avatar      image.Image
+	namePlate   image.Image
+	avatarPanel image.Image
+	tailDown    image.Image
+	tailUp      image.Image
 }
 
 // New creates a caching theme only caches images
@@ -27,6 +31,14 @@ func New(source Source, err error) (*t, error) {
 
 var _ theme.Theme = (*t)(nil)
 var _ theme.Frame = (*t)(nil)
+var _ theme.FrameTiling = (*t)(nil)
+var _ theme.Validator = (*t)(nil)
+var _ theme.NamePlate = (*t)(nil)
+var _ theme.AvatarPanel = (*t)(nil)
+var _ theme.AnimatedChevron = (*t)(nil)
+var _ theme.Tail = (*t)(nil)
+var _ theme.Spaced = (*t)(nil)
+var _ theme.FontSized = (*t)(nil)
 
 func (t *t) Chevron() image.Image {
 	if t.chevron == nil {
@@ -46,6 +58,105 @@ func (t *t) FrameCenter() image.Rectangle {
 	return t.Source.FrameCenter()
 }
 
+func (t *t) FrameTiled() bool {
+	ft, ok := t.Source.(theme.FrameTiling)
+	return ok && ft.FrameTiled()
+}
+
+// NamePlate is the source's, nil if it doesn't have one
+func (t *t) NamePlate() image.Image {
+	np, ok := t.Source.(theme.NamePlate)
+	if t.namePlate == nil && ok {
+		t.namePlate = np.NamePlate()
+	}
+	return t.namePlate
+}
+
+func (t *t) NamePlateCenter() image.Rectangle {
+	if np, ok := t.Source.(theme.NamePlate); ok {
+		return np.NamePlateCenter()
+	}
+	return image.Rectangle{}
+}
+
+// AvatarPanel is the source's, nil if it doesn't have one
+func (t *t) AvatarPanel() image.Image {
+	ap, ok := t.Source.(theme.AvatarPanel)
+	if t.avatarPanel == nil && ok {
+		t.avatarPanel = ap.AvatarPanel()
+	}
+	return t.avatarPanel
+}
+
+func (t *t) AvatarPanelCenter() image.Rectangle {
+	if ap, ok := t.Source.(theme.AvatarPanel); ok {
+		return ap.AvatarPanelCenter()
+	}
+	return image.Rectangle{}
+}
+
+// ChevronFrames is the source's, nil if it doesn't animate the chevron
+func (t *t) ChevronFrames() []theme.ChevronFrame {
+	if ac, ok := t.Source.(theme.AnimatedChevron); ok {
+		return ac.ChevronFrames()
+	}
+	return nil
+}
+
+// TailDown is the source's, nil if it doesn't have a tail
+func (t *t) TailDown() image.Image {
+	tl, ok := t.Source.(theme.Tail)
+	if t.tailDown == nil && ok {
+		t.tailDown = tl.TailDown()
+	}
+	return t.tailDown
+}
+
+// TailUp is the source's, nil if it doesn't have a tail
+func (t *t) TailUp() image.Image {
+	tl, ok := t.Source.(theme.Tail)
+	if t.tailUp == nil && ok {
+		t.tailUp = tl.TailUp()
+	}
+	return t.tailUp
+}
+
+func (t *t) TailOverlap() int {
+	if tl, ok := t.Source.(theme.Tail); ok {
+		return tl.TailOverlap()
+	}
+	return 0
+}
+
+// Spacing is the source's, no spacing if it doesn't have any
+func (t *t) Spacing() theme.Spacing {
+	if s, ok := t.Source.(theme.Spaced); ok {
+		return s.Spacing()
+	}
+	return theme.Spacing{}
+}
+
+// FontSize is the source's, 0 if it doesn't have one
+func (t *t) FontSize() float64 {
+	if fs, ok := t.Source.(theme.FontSized); ok {
+		return fs.FontSize()
+	}
+	return 0
+}
+
+// FontDPI is the source's, 0 if it doesn't have one
+func (t *t) FontDPI() float64 {
+	if fs, ok := t.Source.(theme.FontSized); ok {
+		return fs.FontDPI()
+	}
+	return 0
+}
+
+// Validate validates the source
+func (t *t) Validate() error {
+	return theme.Validate(t.Source)
+}
+
 func (t *t) Avatar() image.Image {
 	if t.avatar == nil {
 		t.avatar = t.Source.Avatar()
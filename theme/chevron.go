@@ -0,0 +1,42 @@
+package theme
+
+import (
+	"image"
+	"time"
+)
+
+// ChevronFrame is one frame of an animated chevron
+type ChevronFrame struct {
+	// Image is drawn in place of the theme's Chevron, it should be the same size
+	Image image.Image
+	// Duration is how long the frame is shown for
+	Duration time.Duration
+}
+
+// AnimatedChevron is an optional extension which animates the more chevron, such as making it bounce or blink, while
+// the text box waits for user input. No frames is the same as not having it
+type AnimatedChevron interface {
+	ChevronFrames() []ChevronFrame
+}
+
+// ChevronFramesFromSheet cuts a sprite sheet into frames of size, left to right then top to bottom, each shown for
+// duration
+func ChevronFramesFromSheet(sheet image.Image, size image.Point, duration time.Duration) []ChevronFrame {
+	si, ok := sheet.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok || size.X <= 0 || size.Y <= 0 {
+		return nil
+	}
+	b := sheet.Bounds()
+	var frames []ChevronFrame
+	for y := b.Min.Y; y+size.Y <= b.Max.Y; y += size.Y {
+		for x := b.Min.X; x+size.X <= b.Max.X; x += size.X {
+			frames = append(frames, ChevronFrame{
+				Image:    si.SubImage(image.Rectangle{Max: size}.Add(image.Pt(x, y))),
+				Duration: duration,
+			})
+		}
+	}
+	return frames
+}
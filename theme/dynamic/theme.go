@@ -5,12 +5,14 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	"sync"
 
 	"github.com/arran4/go-pattern/dsl"
 	pattern_cli "github.com/arran4/go-pattern/pkg/pattern-cli"
 	"github.com/arran4/golang-frame/frames"
 	"github.com/arran4/golang-rpg-textbox/theme"
 	"github.com/arran4/golang-rpg-textbox/theme/cache"
+	"github.com/arran4/golang-rpg-textbox/util"
 	"golang.org/x/image/font"
 )
 
@@ -19,6 +21,9 @@ type t struct {
 	frameName  string
 	patternStr string
 	fontColor  string
+	frameOnce  sync.Once
+	frameImg   image.Image
+	frameErr   error
 }
 
 func New(source cache.Source, frameName, patternStr, fontColor string) *t {
@@ -32,23 +37,58 @@ func New(source cache.Source, frameName, patternStr, fontColor string) *t {
 
 var _ theme.Theme = (*t)(nil)
 var _ theme.Frame = (*t)(nil)
+var _ theme.FrameTiling = (*t)(nil)
+var _ theme.Validator = (*t)(nil)
+var _ theme.NamePlate = (*t)(nil)
+var _ theme.AvatarPanel = (*t)(nil)
+var _ theme.AnimatedChevron = (*t)(nil)
+var _ theme.Tail = (*t)(nil)
+var _ theme.Spaced = (*t)(nil)
+var _ theme.FontSized = (*t)(nil)
 
+// Frame is nil if the frame name or pattern is invalid, use Validate to get the error
 func (t *t) Frame() image.Image {
+	fImg, _ := t.frame()
+	return fImg
+}
+
+// Validate checks the source theme, the frame name, the pattern and the font color
+func (t *t) Validate() error {
+	if err := theme.Validate(t.Source); err != nil {
+		return err
+	}
+	if _, err := t.frame(); err != nil {
+		return err
+	}
+	_, err := t.textColor()
+	return err
+}
+
+// frame is the rendered frame, rendered the first time it is needed
+func (t *t) frame() (image.Image, error) {
+	t.frameOnce.Do(func() {
+		t.frameImg, t.frameErr = t.render()
+	})
+	return t.frameImg, t.frameErr
+}
+
+// render is the named frame or the source's with the pattern drawn behind it
+func (t *t) render() (image.Image, error) {
 	var fImg image.Image
 	if t.frameName != "" {
 		if def, ok := frames.ByName[t.frameName]; ok {
 			fImg = def.Image
 		} else {
-			panic(fmt.Sprintf("unknown frame: %s", t.frameName))
+			return nil, fmt.Errorf("unknown frame: %s", t.frameName)
 		}
-	} else {
-		fImg = t.Source.Frame()
+	} else if fImg = t.Source.Frame(); fImg == nil {
+		return nil, fmt.Errorf("frame: %w", theme.ErrNoImage)
 	}
 
 	if t.patternStr != "" {
 		p, err := dsl.Parse(t.patternStr)
 		if err != nil {
-			panic(fmt.Errorf("invalid pattern %q: %w", t.patternStr, err))
+			return nil, fmt.Errorf("invalid pattern %q: %w", t.patternStr, err)
 		}
 		fm := make(dsl.FuncMap)
 		pattern_cli.RegisterGeneratedCommands(fm)
@@ -56,7 +96,7 @@ func (t *t) Frame() image.Image {
 		bounds := fImg.Bounds()
 		bgImg, err := p.Execute(fm, image.NewRGBA(bounds))
 		if err != nil {
-			panic(fmt.Errorf("failed to execute pattern: %w", err))
+			return nil, fmt.Errorf("failed to execute pattern: %w", err)
 		}
 
 		result := image.NewRGBA(bounds)
@@ -64,10 +104,10 @@ func (t *t) Frame() image.Image {
 		draw.Draw(result, bounds, bgImg, bgImg.Bounds().Min, draw.Src)
 		// Overlay frame
 		draw.Draw(result, bounds, fImg, bounds.Min, draw.Over)
-		return result
+		return result, nil
 	}
 
-	return fImg
+	return fImg, nil
 }
 
 func (t *t) FrameCenter() image.Rectangle {
@@ -78,16 +118,118 @@ func (t *t) FrameCenter() image.Rectangle {
 	}
 	return t.Source.FrameCenter()
 }
+
+// FrameTiled is the source's unless the frame has been replaced
+func (t *t) FrameTiled() bool {
+	ft, ok := t.Source.(theme.FrameTiling)
+	return ok && t.frameName == "" && ft.FrameTiled()
+}
+
+// NamePlate is the source's, nil if it doesn't have one
+func (t *t) NamePlate() image.Image {
+	if np, ok := t.Source.(theme.NamePlate); ok {
+		return np.NamePlate()
+	}
+	return nil
+}
+
+func (t *t) NamePlateCenter() image.Rectangle {
+	if np, ok := t.Source.(theme.NamePlate); ok {
+		return np.NamePlateCenter()
+	}
+	return image.Rectangle{}
+}
+
+// AvatarPanel is the source's, nil if it doesn't have one
+func (t *t) AvatarPanel() image.Image {
+	if ap, ok := t.Source.(theme.AvatarPanel); ok {
+		return ap.AvatarPanel()
+	}
+	return nil
+}
+
+func (t *t) AvatarPanelCenter() image.Rectangle {
+	if ap, ok := t.Source.(theme.AvatarPanel); ok {
+		return ap.AvatarPanelCenter()
+	}
+	return image.Rectangle{}
+}
+
+// ChevronFrames is the source's, nil if it doesn't animate the chevron
+func (t *t) ChevronFrames() []theme.ChevronFrame {
+	if ac, ok := t.Source.(theme.AnimatedChevron); ok {
+		return ac.ChevronFrames()
+	}
+	return nil
+}
+
+// TailDown is the source's, nil if it doesn't have a tail
+func (t *t) TailDown() image.Image {
+	if tl, ok := t.Source.(theme.Tail); ok {
+		return tl.TailDown()
+	}
+	return nil
+}
+
+// TailUp is the source's, nil if it doesn't have a tail
+func (t *t) TailUp() image.Image {
+	if tl, ok := t.Source.(theme.Tail); ok {
+		return tl.TailUp()
+	}
+	return nil
+}
+
+func (t *t) TailOverlap() int {
+	if tl, ok := t.Source.(theme.Tail); ok {
+		return tl.TailOverlap()
+	}
+	return 0
+}
+
+// Spacing is the source's, no spacing if it doesn't have any
+func (t *t) Spacing() theme.Spacing {
+	if s, ok := t.Source.(theme.Spaced); ok {
+		return s.Spacing()
+	}
+	return theme.Spacing{}
+}
+
+// FontSize is the source's, 0 if it doesn't have one
+func (t *t) FontSize() float64 {
+	if fs, ok := t.Source.(theme.FontSized); ok {
+		return fs.FontSize()
+	}
+	return 0
+}
+
+// FontDPI is the source's, 0 if it doesn't have one
+func (t *t) FontDPI() float64 {
+	if fs, ok := t.Source.(theme.FontSized); ok {
+		return fs.FontDPI()
+	}
+	return 0
+}
+
+// FontDrawer is the source's, with the text color replaced by fontColor if there is one
 func (t *t) FontDrawer() *font.Drawer {
 	fd := t.Source.FontDrawer()
+	if c, err := t.textColor(); err == nil && c != nil {
+		fd.Src = image.NewUniform(c)
+	}
+	return fd
+}
+
+// textColor parses fontColor, nil to keep the source's color
+func (t *t) textColor() (color.Color, error) {
 	switch t.fontColor {
+	case "":
+		return nil, nil
 	case "white":
-		fd.Src = image.NewUniform(color.RGBA{240, 240, 240, 255})
-	case "black", "":
-		fd.Src = image.NewUniform(color.Black)
-	default:
-		// fallback
-		fd.Src = image.NewUniform(color.Black)
+		return color.RGBA{240, 240, 240, 255}, nil
 	}
-	return fd
+	c, err := util.ParseColor(t.fontColor)
+	if err != nil {
+		return nil, fmt.Errorf("font color: %w", err)
+	}
+	return c, nil
 }
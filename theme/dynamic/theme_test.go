@@ -0,0 +1,44 @@
+package dynamic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/arran4/golang-rpg-textbox/theme"
+	"github.com/arran4/golang-rpg-textbox/theme/cache"
+	"github.com/arran4/golang-rpg-textbox/theme/simple"
+	"golang.org/x/image/font"
+)
+
+// blueTheme has blue text
+type blueTheme struct {
+	cache.Source
+}
+
+func (bt *blueTheme) FontDrawer() *font.Drawer {
+	fd := bt.Source.FontDrawer()
+	fd.Src = image.NewUniform(color.RGBA{B: 255, A: 255})
+	return fd
+}
+
+func TestFontColor(t *testing.T) {
+	th, err := simple.New()
+	if err != nil {
+		t.Fatalf("Failed to create simple theme: %v", err)
+	}
+	src := &blueTheme{th}
+	for fontColor, want := range map[string]color.Color{
+		"":        color.RGBA{B: 255, A: 255},
+		"white":   color.RGBA{240, 240, 240, 255},
+		"black":   color.RGBA{A: 255},
+		"#ff0000": color.NRGBA{R: 255, A: 255},
+	} {
+		if c := New(src, "", "", fontColor).FontDrawer().Src.At(0, 0); c != want {
+			t.Errorf("%q: text color %v want %v", fontColor, c, want)
+		}
+	}
+	if err := theme.Validate(New(src, "", "", "bluish")); err == nil {
+		t.Errorf("Expected an error for an unknown font color")
+	}
+}
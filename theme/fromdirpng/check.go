@@ -0,0 +1,91 @@
+package fromdirpng
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+
+	"github.com/arran4/golang-rpg-textbox/util"
+)
+
+// Problem is something wrong with a theme directory found by Check
+type Problem struct {
+	// Check is what found the problem: manifest, image, frame-center, frame-mode, text-color, font or chevron
+	Check string `json:"check"`
+	// File is the file the problem is in, if there is one
+	File string `json:"file,omitempty"`
+	// Message describes the problem
+	Message string `json:"message"`
+	// Warning is true if the theme still works but won't look right in some layouts
+	Warning bool `json:"warning,omitempty"`
+}
+
+// Error implements error
+func (p Problem) Error() string {
+	s := fmt.Sprintf("%s: %s", p.Check, p.Message)
+	if p.File != "" {
+		s = p.File + ": " + s
+	}
+	if p.Warning {
+		s = "warning: " + s
+	}
+	return s
+}
+
+// Errors returns the problems that aren't warnings
+func Errors(problems []Problem) []Problem {
+	var errs []Problem
+	for _, p := range problems {
+		if !p.Warning {
+			errs = append(errs, p)
+		}
+	}
+	return errs
+}
+
+// Check loads the theme directory dir and returns every problem found, nil if there are none. Besides everything New
+// and Validate report it checks the frame center is inside the frame and that the chevron fits in the frame's bottom
+// border as the OnFrame chevron locations would otherwise push the text up.
+func Check(dir string) []Problem {
+	return CheckFS(dirFS(dir))
+}
+
+// CheckFS is Check for the root of fsys
+func CheckFS(fsys fs.FS) []Problem {
+	m, err := ReadManifestFS(fsys)
+	if err != nil {
+		return []Problem{{Check: "manifest", Message: err.Error()}}
+	}
+	var problems []Problem
+	images := map[string]image.Image{}
+	for _, name := range []string{m.Chevron, m.Frame, m.Avatar} {
+		i, err := util.LoadImageFS(fsys, name)
+		if err != nil {
+			problems = append(problems, Problem{Check: "image", File: name, Message: err.Error()})
+			continue
+		}
+		images[name] = i
+	}
+	center, err := m.frameCenter()
+	if err != nil {
+		problems = append(problems, Problem{Check: "frame-center", Message: err.Error()})
+	} else if frame := images[m.Frame]; frame != nil {
+		if !center.In(frame.Bounds()) {
+			problems = append(problems, Problem{Check: "frame-center", File: m.Frame, Message: fmt.Sprintf("center %v is outside the frame %v", center, frame.Bounds())})
+		} else if chevron := images[m.Chevron]; chevron != nil {
+			if border := frame.Bounds().Max.Y - center.Max.Y; chevron.Bounds().Dy() > border {
+				problems = append(problems, Problem{Check: "chevron", File: m.Chevron, Message: fmt.Sprintf("chevron is %d pixels tall, taller than the frame's %d pixel bottom border used by the OnFrame chevron locations", chevron.Bounds().Dy(), border)})
+			}
+		}
+	}
+	if _, err := m.tiled(); err != nil {
+		problems = append(problems, Problem{Check: "frame-mode", Message: err.Error()})
+	}
+	if _, err := m.textColor(); err != nil {
+		problems = append(problems, Problem{Check: "text-color", Message: err.Error()})
+	}
+	if _, err := m.fontFace(fsys); err != nil {
+		problems = append(problems, Problem{Check: "font", File: m.Font, Message: err.Error()})
+	}
+	return problems
+}
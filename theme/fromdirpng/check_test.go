@@ -0,0 +1,88 @@
+package fromdirpng
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCheck(t *testing.T) {
+	if problems := Check(filepath.Join("..", "simple")); len(problems) != 0 {
+		t.Errorf("Expected the simple theme to pass without warnings got %v", problems)
+	}
+	dir := themeDir(t, "theme.json", `{
+	"chevron": "missing.png",
+	"frame": "border.png",
+	"frameCenter": [30, 30, 600, 60],
+	"avatar": "face.png",
+	"font": "missing.ttf",
+	"textColor": "bluish"
+}`)
+	if err := os.WriteFile(filepath.Join(dir, "face.png"), []byte("not a png"), 0644); err != nil {
+		t.Fatalf("Writing face.png: %v", err)
+	}
+	found := map[string]string{}
+	for _, p := range Check(dir) {
+		if p.Warning {
+			t.Errorf("Unexpected warning %v", p)
+		}
+		found[p.Check+" "+p.File] = p.Message
+	}
+	for _, want := range []string{"image missing.png", "image face.png", "frame-center border.png", "font missing.ttf", "text-color "} {
+		if _, ok := found[want]; !ok {
+			t.Errorf("Expected a %q problem got %v", want, found)
+		}
+	}
+	if len(found) != 5 {
+		t.Errorf("Expected 5 problems got %v", found)
+	}
+}
+
+func TestCheckFS_TallChevron(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, b := range simpleFiles(t, "") {
+		fsys[name] = &fstest.MapFile{Data: b}
+	}
+	// border.png is 94 pixels tall with the center ending at 58, a 36 pixel bottom border
+	for height, fails := range map[int]bool{36: false, 37: true} {
+		buf := bytes.NewBuffer(nil)
+		if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 20, height))); err != nil {
+			t.Fatalf("Encoding chevron: %v", err)
+		}
+		fsys["more.png"] = &fstest.MapFile{Data: buf.Bytes()}
+		problems := Errors(CheckFS(fsys))
+		if fails && (len(problems) != 1 || problems[0].Check != "chevron" || problems[0].File != "more.png") {
+			t.Errorf("%d: Expected a chevron error got %v", height, problems)
+		} else if !fails && len(problems) != 0 {
+			t.Errorf("%d: Expected no problems got %v", height, problems)
+		}
+	}
+}
+
+func TestCheckFS_JPEG(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, b := range simpleFiles(t, "") {
+		fsys[name] = &fstest.MapFile{Data: b}
+	}
+	// JPEG data decodes to a read only *image.YCbCr, named .png as the decoder goes by content
+	buf := bytes.NewBuffer(nil)
+	if err := jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatalf("Encoding jpeg: %v", err)
+	}
+	fsys["face.png"] = &fstest.MapFile{Data: buf.Bytes()}
+	if problems := Errors(CheckFS(fsys)); len(problems) != 0 {
+		t.Errorf("Expected a JPEG avatar to pass got %v", problems)
+	}
+	th, err := LoadFS(fsys, nil)
+	if err != nil {
+		t.Fatalf("LoadFS: %v", err)
+	}
+	if got := th.Avatar().Bounds(); got != image.Rect(0, 0, 16, 16) {
+		t.Errorf("Avatar().Bounds() = %v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package fromdirpng
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/arran4/golang-rpg-textbox/util"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFiles are the names a manifest is looked for under in the theme directory, in order
+var ManifestFiles = []string{"theme.json", "theme.yaml", "theme.yml"}
+
+// Manifest declares the contents of a theme directory. Paths are slash separated and relative to the directory, they
+// can't leave it. Fields left empty take the value from DefaultManifest.
+type Manifest struct {
+	// Chevron is the chevron image
+	Chevron string `json:"chevron,omitempty" yaml:"chevron,omitempty"`
+	// Frame is the frame image, a nine-slice image split by FrameCenter
+	Frame string `json:"frame,omitempty" yaml:"frame,omitempty"`
+	// FrameCenter is the center of the frame image as [minX, minY, maxX, maxY]
+	FrameCenter []int `json:"frameCenter,omitempty" yaml:"frameCenter,omitempty,flow"`
+	// FrameMode is "stretch" to stretch the frame's edges and center to size or "tile" to repeat them
+	FrameMode string `json:"frameMode,omitempty" yaml:"frameMode,omitempty"`
+	// Avatar is the avatar image
+	Avatar string `json:"avatar,omitempty" yaml:"avatar,omitempty"`
+	// Font is a TrueType font file or the name of a built in font such as goregular. Without it the font face given to
+	// New is used
+	Font string `json:"font,omitempty" yaml:"font,omitempty"`
+	// FontSize is the size of the Font in points
+	FontSize float64 `json:"fontSize,omitempty" yaml:"fontSize,omitempty"`
+	// DPI the Font is rendered at
+	DPI float64 `json:"dpi,omitempty" yaml:"dpi,omitempty"`
+	// TextColor is the color of the text, a color name or #rgb, #rrggbb or #rrggbbaa
+	TextColor string `json:"textColor,omitempty" yaml:"textColor,omitempty"`
+}
+
+// DefaultManifest describes a theme directory without a manifest file
+var DefaultManifest = Manifest{
+	Chevron:     "chevron.png",
+	Frame:       "frame.png",
+	FrameCenter: []int{35, 34, 63, 58},
+	FrameMode:   "stretch",
+	Avatar:      "avatar.png",
+	FontSize:    16,
+	DPI:         75,
+	TextColor:   "#000000",
+}
+
+// ReadManifest reads the first of ManifestFiles found in dir, a .json file as JSON and anything else as YAML. If there
+// is none DefaultManifest is returned.
+func ReadManifest(dir string) (*Manifest, error) {
+	return ReadManifestFS(dirFS(dir))
+}
+
+// ReadManifestFS is ReadManifest for the root of fsys
+func ReadManifestFS(fsys fs.FS) (*Manifest, error) {
+	m := DefaultManifest
+	for _, name := range ManifestFiles {
+		b, err := fs.ReadFile(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("reading manifest %s: %w", name, err)
+		}
+		var read Manifest
+		if path.Ext(name) == ".json" {
+			err = json.Unmarshal(b, &read)
+		} else {
+			err = yaml.Unmarshal(b, &read)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing manifest %s: %w", name, err)
+		}
+		m.merge(&read)
+		break
+	}
+	return &m, nil
+}
+
+// WriteManifest writes m to dir as theme.yaml
+func WriteManifest(dir string, m *Manifest) error {
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "theme.yaml"), b, 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+// merge replaces the fields of m with those set in o
+func (m *Manifest) merge(o *Manifest) {
+	for _, f := range []struct{ dst, src *string }{
+		{&m.Chevron, &o.Chevron},
+		{&m.Frame, &o.Frame},
+		{&m.FrameMode, &o.FrameMode},
+		{&m.Avatar, &o.Avatar},
+		{&m.Font, &o.Font},
+		{&m.TextColor, &o.TextColor},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	if o.FrameCenter != nil {
+		m.FrameCenter = o.FrameCenter
+	}
+	if o.FontSize != 0 {
+		m.FontSize = o.FontSize
+	}
+	if o.DPI != 0 {
+		m.DPI = o.DPI
+	}
+}
+
+// frameCenter converts FrameCenter to a rectangle
+func (m *Manifest) frameCenter() (image.Rectangle, error) {
+	if len(m.FrameCenter) != 4 {
+		return image.Rectangle{}, fmt.Errorf("frame center needs 4 values [minX, minY, maxX, maxY] got %d", len(m.FrameCenter))
+	}
+	r := image.Rectangle{
+		Min: image.Pt(m.FrameCenter[0], m.FrameCenter[1]),
+		Max: image.Pt(m.FrameCenter[2], m.FrameCenter[3]),
+	}
+	if r.Empty() {
+		return image.Rectangle{}, fmt.Errorf("frame center %v is empty", m.FrameCenter)
+	}
+	return r, nil
+}
+
+// tiled returns true if FrameMode is tile
+func (m *Manifest) tiled() (bool, error) {
+	switch m.FrameMode {
+	case "", "stretch":
+		return false, nil
+	case "tile":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown frame mode %q, expected stretch or tile", m.FrameMode)
+}
+
+// fontFace loads the Font from fsys, nil if the manifest doesn't have one
+func (m *Manifest) fontFace(fsys fs.FS) (font.Face, error) {
+	if m.Font == "" {
+		return nil, nil
+	}
+	b, err := util.FontByName(m.Font)
+	if err != nil {
+		if b, err = fs.ReadFile(fsys, m.Font); err != nil {
+			return nil, fmt.Errorf("reading font %s: %w", m.Font, err)
+		}
+	}
+	f, err := truetype.Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing font %s: %w", m.Font, err)
+	}
+	return util.GetFontFace(m.FontSize, m.DPI, f), nil
+}
+
+// textColor parses TextColor
+func (m *Manifest) textColor() (color.Color, error) {
+	c, err := util.ParseColor(m.TextColor)
+	if err != nil {
+		return nil, fmt.Errorf("text color: %w", err)
+	}
+	return c, nil
+}
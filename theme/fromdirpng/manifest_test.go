@@ -0,0 +1,145 @@
+package fromdirpng
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// themeDir creates a theme directory with the simple theme's images renamed and a manifest file
+func themeDir(t *testing.T, manifestName, manifest string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for from, to := range map[string]string{"chevron.png": "more.png", "frame.png": "border.png", "avatar.png": "face.png"} {
+		b, err := os.ReadFile(filepath.Join("..", "simple", from))
+		if err != nil {
+			t.Fatalf("Reading %s: %v", from, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, to), b, 0644); err != nil {
+			t.Fatalf("Writing %s: %v", to, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestName), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Writing manifest: %v", err)
+	}
+	return dir
+}
+
+func TestManifest(t *testing.T) {
+	tests := []struct {
+		name, file, manifest string
+	}{
+		{"JSON", "theme.json", `{
+	"chevron": "more.png",
+	"frame": "border.png",
+	"frameCenter": [30, 30, 60, 60],
+	"frameMode": "tile",
+	"avatar": "face.png",
+	"font": "gobold",
+	"fontSize": 20,
+	"dpi": 72,
+	"textColor": "#ff000080"
+}`},
+		{"YAML", "theme.yaml", `chevron: more.png
+frame: border.png
+frameCenter: [30, 30, 60, 60]
+frameMode: tile
+avatar: face.png
+font: gobold
+fontSize: 20
+dpi: 72
+textColor: "#ff000080"
+`},
+	}
+	for _, tt := range tests {
+		th, err := New(themeDir(t, tt.file, tt.manifest), nil)
+		if err != nil {
+			t.Fatalf("%s: Failed to create theme: %v", tt.name, err)
+		}
+		if got, want := th.FrameCenter(), image.Rect(30, 30, 60, 60); got != want {
+			t.Errorf("%s: FrameCenter() = %v want %v", tt.name, got, want)
+		}
+		if !th.FrameTiled() {
+			t.Errorf("%s: expected a tiled frame", tt.name)
+		}
+		if th.Chevron() == nil || th.Frame() == nil || th.Avatar() == nil {
+			t.Errorf("%s: expected the renamed images to load", tt.name)
+		}
+		if th.FontFace() == nil {
+			t.Fatalf("%s: expected the manifest's font", tt.name)
+		}
+		if h := th.FontFace().Metrics().Height.Ceil(); h < 20 {
+			t.Errorf("%s: font height %d expected a 20 point font", tt.name, h)
+		}
+		if th.FontSize() != 20 || th.FontDPI() != 72 {
+			t.Errorf("%s: font size %v dpi %v want 20 72", tt.name, th.FontSize(), th.FontDPI())
+		}
+		if c := th.FontDrawer().Src.At(0, 0); c != (color.NRGBA{R: 255, A: 128}) {
+			t.Errorf("%s: text color %v", tt.name, c)
+		}
+	}
+}
+
+func TestManifestDefaults(t *testing.T) {
+	th, err := New(filepath.Join("..", "simple"), nil)
+	if err != nil {
+		t.Fatalf("Failed to create theme: %v", err)
+	}
+	if got, want := th.FrameCenter(), image.Rect(35, 34, 63, 58); got != want {
+		t.Errorf("FrameCenter() = %v want %v", got, want)
+	}
+	if th.FrameTiled() {
+		t.Errorf("Expected a stretched frame")
+	}
+	if th.FontSize() != 0 {
+		t.Errorf("Expected no font size without a font in the manifest got %v", th.FontSize())
+	}
+	if r, g, b, _ := th.FontDrawer().Src.At(0, 0).RGBA(); r|g|b != 0 {
+		t.Errorf("Expected black text")
+	}
+}
+
+func TestManifestColorName(t *testing.T) {
+	th, err := New(themeDir(t, "theme.json", `{"textColor": "Red"}`), nil)
+	if err != nil {
+		t.Fatalf("Failed to create theme: %v", err)
+	}
+	if c := th.FontDrawer().Src.At(0, 0); c != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("text color %v want red", c)
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	for name, manifest := range map[string]string{
+		"Syntax":      `{"frame": `,
+		"FrameCenter": `{"frameCenter": [1, 2, 3]}`,
+		"EmptyCenter": `{"frameCenter": [10, 10, 5, 20]}`,
+		"FrameMode":   `{"frameMode": "wobble"}`,
+		"TextColor":   `{"textColor": "reddish"}`,
+		"Font":        `{"font": "missing.ttf"}`,
+	} {
+		if _, err := New(themeDir(t, "theme.json", manifest), nil); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWriteManifest(t *testing.T) {
+	dir := t.TempDir()
+	want := DefaultManifest
+	want.FrameCenter = []int{10, 12, 40, 30}
+	want.FrameMode = "tile"
+	if err := WriteManifest(dir, &want); err != nil {
+		t.Fatalf("WriteManifest: %v", err)
+	}
+	got, err := ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("ReadManifest() = %+v want %+v", *got, want)
+	}
+}
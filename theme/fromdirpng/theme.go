@@ -2,77 +2,165 @@ package fromdirpng
 
 import (
 	_ "embed"
+	"errors"
+	"fmt"
 	"github.com/arran4/golang-rpg-textbox/theme"
 	"github.com/arran4/golang-rpg-textbox/util"
 	"golang.org/x/image/font"
 	"image"
-	"path/filepath"
+	"image/color"
+	"io/fs"
+	"os"
 	"sync"
 )
 
 type t struct {
-	dir      string
-	fontFace font.Face
-	mu       sync.Mutex
-	chevron  image.Image
-	frame    image.Image
-	avatar   image.Image
+	fsys        fs.FS
+	manifest    *Manifest
+	fontFace    font.Face
+	frameCenter image.Rectangle
+	tiled       bool
+	textColor   color.Color
+	mu          sync.Mutex
+	chevron     image.Image
+	frame       image.Image
+	avatar      image.Image
 }
 
-// New creates a new theme from a directory location, it assumes all files are PNG.
+// New creates a new theme from a directory location, it assumes all files are PNG. The directory's manifest (see
+// ReadManifest) names the files, without one they are chevron.png, frame.png and avatar.png.
 func New(dir string, fontFace font.Face) (*t, error) {
-	return &t{
-		dir:      dir,
+	return NewFS(dirFS(dir), fontFace)
+}
+
+// NewFS creates a new theme from the same layout as New at the root of fsys, such as an embed.FS, a zip.Reader (see
+// OpenZip) or a subdirectory of either from fs.Sub.
+func NewFS(fsys fs.FS, fontFace font.Face) (*t, error) {
+	m, err := ReadManifestFS(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromManifestFS(fsys, m, fontFace)
+}
+
+// NewFromManifest creates a new theme from the files m declares in dir. The manifest's font replaces fontFace if it
+// has one.
+func NewFromManifest(dir string, m *Manifest, fontFace font.Face) (*t, error) {
+	return NewFromManifestFS(dirFS(dir), m, fontFace)
+}
+
+// NewFromManifestFS is NewFromManifest for the root of fsys
+func NewFromManifestFS(fsys fs.FS, m *Manifest, fontFace font.Face) (*t, error) {
+	th := &t{
+		fsys:     fsys,
+		manifest: m,
 		fontFace: fontFace,
-	}, nil
+	}
+	var err error
+	if th.frameCenter, err = m.frameCenter(); err != nil {
+		return nil, err
+	}
+	if th.tiled, err = m.tiled(); err != nil {
+		return nil, err
+	}
+	if th.textColor, err = m.textColor(); err != nil {
+		return nil, err
+	}
+	if ff, err := m.fontFace(fsys); err != nil {
+		return nil, err
+	} else if ff != nil {
+		th.fontFace = ff
+	}
+	return th, nil
+}
+
+// dirFS is os.DirFS treating an empty dir as the working directory like the relative paths it replaces
+func dirFS(dir string) fs.FS {
+	if dir == "" {
+		dir = "."
+	}
+	return os.DirFS(dir)
 }
 
 var _ theme.Theme = (*t)(nil)
 var _ theme.Frame = (*t)(nil)
+var _ theme.FrameTiling = (*t)(nil)
+var _ theme.Validator = (*t)(nil)
+var _ theme.FontSized = (*t)(nil)
 
-func (t *t) Chevron() image.Image {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	if t.chevron != nil {
-		return t.chevron
-	}
-	chevron, err := util.LoadImageFile(filepath.Join(t.dir, "chevron.png"))
+// Load creates a new theme like New and loads every file up front, returning any that are missing or invalid
+func Load(dir string, fontFace font.Face) (*t, error) {
+	return LoadFS(dirFS(dir), fontFace)
+}
+
+// LoadFS creates a new theme like NewFS and loads every file up front like Load
+func LoadFS(fsys fs.FS, fontFace font.Face) (*t, error) {
+	th, err := NewFS(fsys, fontFace)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	t.chevron = chevron
-	return chevron
+	if err := th.Validate(); err != nil {
+		return nil, err
+	}
+	return th, nil
 }
 
-func (t *t) Frame() image.Image {
+// Validate loads every file the theme uses, returning all the problems found
+func (t *t) Validate() error {
+	var errs []error
+	for _, f := range []struct {
+		name   string
+		cached *image.Image
+	}{
+		{t.manifest.Chevron, &t.chevron},
+		{t.manifest.Frame, &t.frame},
+		{t.manifest.Avatar, &t.avatar},
+	} {
+		if _, err := t.load(f.name, f.cached); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load returns the image cached or loads it from the file name
+func (t *t) load(name string, cached *image.Image) (image.Image, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	if t.frame != nil {
-		return t.frame
+	if *cached != nil {
+		return *cached, nil
 	}
-	frame, err := util.LoadImageFile(filepath.Join(t.dir, "frame.png"))
+	i, err := util.LoadImageFS(t.fsys, name)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("loading %s: %w", name, err)
 	}
-	t.frame = frame
+	*cached = i
+	return i, nil
+}
+
+// Chevron is nil if the file can't be loaded, use Load or Validate to get the error
+func (t *t) Chevron() image.Image {
+	chevron, _ := t.load(t.manifest.Chevron, &t.chevron)
+	return chevron
+}
+
+// Frame is nil if the file can't be loaded, use Load or Validate to get the error
+func (t *t) Frame() image.Image {
+	frame, _ := t.load(t.manifest.Frame, &t.frame)
 	return frame
 }
 
 func (t *t) FrameCenter() image.Rectangle {
-	return image.Rect(35, 34, 63, 58)
+	return t.frameCenter
+}
+
+func (t *t) FrameTiled() bool {
+	return t.tiled
 }
 
+// Avatar is nil if the file can't be loaded, use Load or Validate to get the error
 func (t *t) Avatar() image.Image {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	if t.avatar != nil {
-		return t.avatar
-	}
-	person, err := util.LoadImageFile(filepath.Join(t.dir, "avatar.png"))
-	if err != nil {
-		panic(err)
-	}
-	t.avatar = person
+	person, _ := t.load(t.manifest.Avatar, &t.avatar)
 	return person
 }
 
@@ -80,9 +168,26 @@ func (t *t) FontFace() font.Face {
 	return t.fontFace
 }
 
+// FontSize is the manifest's FontSize if the manifest has a Font, otherwise 0 as the size of the font face given to New
+// isn't known
+func (t *t) FontSize() float64 {
+	if t.manifest.Font == "" {
+		return 0
+	}
+	return t.manifest.FontSize
+}
+
+// FontDPI is the manifest's DPI if the manifest has a Font, otherwise 0
+func (t *t) FontDPI() float64 {
+	if t.manifest.Font == "" {
+		return 0
+	}
+	return t.manifest.DPI
+}
+
 func (t *t) FontDrawer() *font.Drawer {
 	return &font.Drawer{
-		Src:  image.NewUniform(image.Black),
+		Src:  image.NewUniform(t.textColor),
 		Face: t.FontFace(),
 	}
 }
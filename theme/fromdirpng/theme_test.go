@@ -2,6 +2,7 @@ package fromdirpng
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -26,11 +27,23 @@ func TestChevron_MissingFiles(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Expected no error when creating theme from empty directory (lazy loading), got %v", err)
 	}
+	if c := theme.Chevron(); c != nil {
+		t.Errorf("Expected no chevron for a missing file got %v", c.Bounds())
+	}
+}
 
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("Expected panic when accessing missing file, got none")
+func TestLoad_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(dir, nil); err == nil {
+		t.Fatal("Expected an error loading an empty directory")
+	} else {
+		for _, name := range []string{"chevron.png", "frame.png", "avatar.png"} {
+			if !strings.Contains(err.Error(), name) {
+				t.Errorf("Expected the error to mention %s got: %v", name, err)
+			}
 		}
-	}()
-	_ = theme.Chevron()
+	}
+	if _, err := Load(filepath.Join("..", "simple"), nil); err != nil {
+		t.Errorf("Expected the simple theme's files to load got: %v", err)
+	}
 }
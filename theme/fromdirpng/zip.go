@@ -0,0 +1,43 @@
+package fromdirpng
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"golang.org/x/image/font"
+)
+
+// OpenZip creates a new theme from the zip archive name, which is read into memory so there is nothing to close. See
+// ZipFS for where in the archive the theme is looked for.
+func OpenZip(name string, fontFace font.Face) (*t, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading theme archive: %w", err)
+	}
+	fsys, err := ZipFS(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+	return NewFS(fsys, fontFace)
+}
+
+// ZipFS opens the zip archive in r for NewFS, LoadFS or CheckFS. The theme is the root of the archive unless the
+// archive holds nothing but a single directory, as zipping up a theme directory gives, then it is that directory.
+func ZipFS(r io.ReaderAt, size int64) (fs.FS, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("reading theme archive: %w", err)
+	}
+	entries, err := fs.ReadDir(z, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading theme archive: %w", err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return fs.Sub(z, entries[0].Name())
+	}
+	return z, nil
+}
@@ -0,0 +1,103 @@
+package fromdirpng
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// simpleFiles returns the simple theme's images renamed like themeDir does, with a manifest, under prefix
+func simpleFiles(t *testing.T, prefix string) map[string][]byte {
+	t.Helper()
+	files := map[string][]byte{
+		path.Join(prefix, "theme.yaml"): []byte("chevron: more.png\nframe: border.png\nframeCenter: [30, 30, 60, 58]\navatar: face.png\n"),
+	}
+	for from, to := range map[string]string{"chevron.png": "more.png", "frame.png": "border.png", "avatar.png": "face.png"} {
+		b, err := os.ReadFile(filepath.Join("..", "simple", from))
+		if err != nil {
+			t.Fatalf("Reading %s: %v", from, err)
+		}
+		files[path.Join(prefix, to)] = b
+	}
+	return files
+}
+
+// zipFiles creates a zip archive of files
+func zipFiles(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+	for name, b := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Creating %s: %v", name, err)
+		}
+		if _, err := w.Write(b); err != nil {
+			t.Fatalf("Writing %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Closing zip: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for name, b := range simpleFiles(t, "") {
+		fsys[name] = &fstest.MapFile{Data: b}
+	}
+	th, err := LoadFS(fsys, nil)
+	if err != nil {
+		t.Fatalf("LoadFS: %v", err)
+	}
+	if got, want := th.FrameCenter(), image.Rect(30, 30, 60, 58); got != want {
+		t.Errorf("FrameCenter() = %v want %v", got, want)
+	}
+	if problems := CheckFS(fsys); len(Errors(problems)) != 0 {
+		t.Errorf("CheckFS: %v", problems)
+	}
+	delete(fsys, "face.png")
+	if _, err := LoadFS(fsys, nil); err == nil {
+		t.Errorf("Expected an error with face.png missing")
+	}
+}
+
+func TestZipFS(t *testing.T) {
+	for _, prefix := range []string{"", "mytheme"} {
+		b := zipFiles(t, simpleFiles(t, prefix))
+		fsys, err := ZipFS(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			t.Fatalf("%q: ZipFS: %v", prefix, err)
+		}
+		th, err := LoadFS(fsys, nil)
+		if err != nil {
+			t.Fatalf("%q: LoadFS: %v", prefix, err)
+		}
+		if got, want := th.FrameCenter(), image.Rect(30, 30, 60, 58); got != want {
+			t.Errorf("%q: FrameCenter() = %v want %v", prefix, got, want)
+		}
+	}
+}
+
+func TestOpenZip(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "theme.zip")
+	if err := os.WriteFile(name, zipFiles(t, simpleFiles(t, "mytheme")), 0644); err != nil {
+		t.Fatalf("Writing zip: %v", err)
+	}
+	th, err := OpenZip(name, nil)
+	if err != nil {
+		t.Fatalf("OpenZip: %v", err)
+	}
+	if err := th.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	if _, err := OpenZip(filepath.Join(t.TempDir(), "missing.zip"), nil); err == nil {
+		t.Errorf("Expected an error for a missing archive")
+	}
+}
@@ -18,3 +18,58 @@ type Frame interface {
 	Frame() image.Image
 	FrameCenter() image.Rectangle
 }
+
+// FrameTiling is an optional extension for a Frame, if FrameTiled returns true the frame's edges and center are
+// repeated to fill the space rather than stretched
+type FrameTiling interface {
+	FrameTiled() bool
+}
+
+// NamePlate is an optional extension which draws the name tag on its own plate, a nine-slice image drawn the same way
+// as Frame. A nil NamePlate is the same as not having one
+type NamePlate interface {
+	NamePlate() image.Image
+	NamePlateCenter() image.Rectangle
+}
+
+// AvatarPanel is an optional extension which frames avatars placed outside the main frame, a nine-slice image drawn
+// the same way as Frame. Without it, or if AvatarPanel is nil, avatar panels use the Frame
+type AvatarPanel interface {
+	AvatarPanel() image.Image
+	AvatarPanelCenter() image.Rectangle
+}
+
+// Tail is an optional extension for speech bubbles, the tail is drawn over the edge of the frame pointing toward the
+// speaker. Nil tail images are the same as not having it
+type Tail interface {
+	// TailDown is drawn below the frame, the tip of the tail is the bottom center of the image
+	TailDown() image.Image
+	// TailUp is drawn above the frame, the tip of the tail is the top center of the image
+	TailUp() image.Image
+	// TailOverlap is how many pixels of the tail are drawn over the frame so that it joins the frame's border
+	TailOverlap() int
+}
+
+// Spacing is the space in pixels left between the parts of a text box
+type Spacing struct {
+	// Padding is the space between the edge of the frame's center and everything inside it
+	Padding int
+	// AvatarGutter is the space between the avatar and the text
+	AvatarGutter int
+	// NameGap is the space between the name tag and what is below it, the text or for names above the frame the frame
+	NameGap int
+	// ChevronOffset moves the more chevron from where its location puts it
+	ChevronOffset image.Point
+}
+
+// Spaced is an optional extension which supplies the default Spacing of text boxes using the theme
+type Spaced interface {
+	Spacing() Spacing
+}
+
+// FontSized is an optional extension which gives the size in points and the DPI FontFace was created with, so that
+// other faces such as those used for markup can match it. A FontSize of 0 is the same as not having it
+type FontSized interface {
+	FontSize() float64
+	FontDPI() float64
+}
@@ -20,6 +20,10 @@ var (
 	FrameBytes []byte
 	//go:embed "avatar.png"
 	AvatarBytes []byte
+	//go:embed "taildown.png"
+	TailDownBytes []byte
+	//go:embed "tailup.png"
+	TailUpBytes []byte
 
 	fontFaceOnce sync.Once
 	fontFace     font.Face
@@ -32,6 +36,12 @@ var (
 
 	avatarOnce sync.Once
 	avatarImg  image.Image
+
+	tailDownOnce sync.Once
+	tailDownImg  image.Image
+
+	tailUpOnce sync.Once
+	tailUpImg  image.Image
 )
 
 type t struct{}
@@ -43,6 +53,8 @@ func New() (*t, error) {
 
 var _ theme.Theme = (*t)(nil)
 var _ theme.Frame = (*t)(nil)
+var _ theme.Tail = (*t)(nil)
+var _ theme.FontSized = (*t)(nil)
 
 func (t *t) Chevron() image.Image {
 	chevronOnce.Do(func() {
@@ -81,6 +93,32 @@ func (t *t) Avatar() image.Image {
 	return avatarImg
 }
 
+func (t *t) TailDown() image.Image {
+	tailDownOnce.Do(func() {
+		var err error
+		tailDownImg, err = png.Decode(bytes.NewReader(TailDownBytes))
+		if err != nil {
+			panic(err)
+		}
+	})
+	return tailDownImg
+}
+
+func (t *t) TailUp() image.Image {
+	tailUpOnce.Do(func() {
+		var err error
+		tailUpImg, err = png.Decode(bytes.NewReader(TailUpBytes))
+		if err != nil {
+			panic(err)
+		}
+	})
+	return tailUpImg
+}
+
+func (t *t) TailOverlap() int {
+	return 16
+}
+
 func (t *t) FontFace() font.Face {
 	fontFaceOnce.Do(func() {
 		f, err := truetype.Parse(goregular.TTF)
@@ -88,13 +126,21 @@ func (t *t) FontFace() font.Face {
 			panic(err)
 		}
 		fontFace = truetype.NewFace(f, &truetype.Options{
-			Size: 16,
-			DPI:  75,
+			Size: t.FontSize(),
+			DPI:  t.FontDPI(),
 		})
 	})
 	return fontFace
 }
 
+func (t *t) FontSize() float64 {
+	return 16
+}
+
+func (t *t) FontDPI() float64 {
+	return 75
+}
+
 func (t *t) FontDrawer() *font.Drawer {
 	return &font.Drawer{
 		Src:  image.NewUniform(image.Black),
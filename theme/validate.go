@@ -0,0 +1,89 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+var (
+	// ErrNoTheme is returned for a nil theme
+	ErrNoTheme = errors.New("no theme")
+	// ErrNoImage is returned for a required image the theme returned nil for
+	ErrNoImage = errors.New("no image")
+)
+
+// Validator is an optional extension for themes which load their resources lazily or can otherwise fail. Validate
+// loads everything up front and returns every problem found, after which the theme's methods shouldn't panic
+type Validator interface {
+	Validate() error
+}
+
+// Validate returns every problem with t as one error, nil if it is usable. Themes implementing Validator check
+// themselves first, then each image t provides is requested with panics returned as errors and nine-slice centers
+// checked against their images.
+func Validate(t Theme) error {
+	if t == nil {
+		return ErrNoTheme
+	}
+	if v, ok := t.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	var errs []error
+	if chevron, err := call("chevron", t.Chevron); err != nil {
+		errs = append(errs, err)
+	} else if chevron == nil {
+		errs = append(errs, fmt.Errorf("chevron: %w", ErrNoImage))
+	}
+	if _, err := call("avatar", t.Avatar); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := call("font drawer", t.FontDrawer); err != nil {
+		errs = append(errs, err)
+	}
+	if f, ok := t.(Frame); ok {
+		errs = append(errs, nineSlice("frame", f.Frame, f.FrameCenter, false))
+	}
+	if np, ok := t.(NamePlate); ok {
+		errs = append(errs, nineSlice("name plate", np.NamePlate, np.NamePlateCenter, true))
+	}
+	if ap, ok := t.(AvatarPanel); ok {
+		errs = append(errs, nineSlice("avatar panel", ap.AvatarPanel, ap.AvatarPanelCenter, true))
+	}
+	return errors.Join(errs...)
+}
+
+// nineSlice checks the image from i has the center from center inside it. optional images may be nil, as wrapping
+// themes return for an extension their source doesn't have
+func nineSlice(name string, i func() image.Image, center func() image.Rectangle, optional bool) error {
+	img, err := call(name, i)
+	if err != nil {
+		return err
+	}
+	if img == nil {
+		if optional {
+			return nil
+		}
+		return fmt.Errorf("%s: %w", name, ErrNoImage)
+	}
+	c, err := call(name+" center", center)
+	if err != nil {
+		return err
+	}
+	if c.Empty() || !c.In(img.Bounds()) {
+		return fmt.Errorf("%s: center %v is not inside the image %v", name, c, img.Bounds())
+	}
+	return nil
+}
+
+// call returns the result of f, or the panic f raised as an error
+func call[T any](name string, f func() T) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: %v", name, r)
+		}
+	}()
+	return f(), nil
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+)
+
+// ParseColor parses a color name (see golang.org/x/image/colornames) or a #rgb, #rrggbb or #rrggbbaa hex value
+func ParseColor(s string) (color.Color, error) {
+	if c, ok := colornames.Map[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	hex, found := strings.CutPrefix(s, "#")
+	if !found {
+		return nil, fmt.Errorf("unknown color %q", s)
+	}
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return nil, fmt.Errorf("invalid color %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
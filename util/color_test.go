@@ -0,0 +1,19 @@
+package util
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	for s, want := range map[string]color.Color{
+		"Red":       color.RGBA{R: 0xff, A: 0xff},
+		"#0f0":      color.NRGBA{G: 0xff, A: 0xff},
+		"#0000ff":   color.NRGBA{B: 0xff, A: 0xff},
+		"#11223344": color.NRGBA{R: 0x11, G: 0x22, B: 0x33, A: 0x44},
+	} {
+		if c, err := ParseColor(s); err != nil || c != want {
+			t.Errorf("ParseColor(%q) = %v, %v want %v", s, c, err, want)
+		}
+	}
+}
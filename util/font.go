@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"github.com/golang/freetype/truetype"
 	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
 	"golang.org/x/image/font/gofont/goregular"
 )
 
@@ -31,6 +34,12 @@ func FontByName(name string) ([]byte, error) {
 	switch name {
 	case "goregular":
 		return goregular.TTF, nil
+	case "gobold":
+		return gobold.TTF, nil
+	case "goitalic":
+		return goitalic.TTF, nil
+	case "gobolditalic":
+		return gobolditalic.TTF, nil
 	}
 	return nil, errors.New("font not found")
 }
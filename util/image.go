@@ -8,6 +8,7 @@ import (
 	"image/gif"
 	_ "image/jpeg"
 	"image/png"
+	"io/fs"
 	"log"
 	"os"
 )
@@ -26,7 +27,37 @@ func LoadImageFile(fn string) (Image, error) {
 	if err != nil {
 		return nil, fmt.Errorf("image encoding: %w", err)
 	}
-	return i.(Image), nil
+	return toImage(i), nil
+}
+
+// LoadImageFS is LoadImageFile for a file in fsys
+func LoadImageFS(fsys fs.FS, fn string) (Image, error) {
+	fi, err := fsys.Open(fn)
+	if err != nil {
+		return nil, fmt.Errorf("file create: %w", err)
+	}
+	defer func() {
+		if err := fi.Close(); err != nil {
+			log.Printf("File close error: %s", err)
+		}
+	}()
+	i, _, err := image.Decode(fi)
+	if err != nil {
+		return nil, fmt.Errorf("image encoding: %w", err)
+	}
+	return toImage(i), nil
+}
+
+// toImage returns i if it is already an Image, otherwise a copy in an *image.NRGBA. Decoders such as JPEG's return
+// read-only images like *image.YCbCr
+func toImage(i image.Image) Image {
+	if ii, ok := i.(Image); ok {
+		return ii
+	}
+	b := i.Bounds()
+	ii := image.NewNRGBA(b)
+	draw.Draw(ii, b, i, b.Min, draw.Src)
+	return ii
 }
 
 func SavePngFile(i Image, fn string) error {